@@ -10,6 +10,17 @@ import (
 
 func init() {
 	spinhttp.Handle(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stream" {
+			// Send the generated text as Server-Sent Events.
+			stream, err := spinhttp.NewEventStream(w)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			stream.SendText(llm.InferStream(r.Context(), "llama2-chat", "Tell me a joke", nil))
+			return
+		}
+
 		result, err := llm.Infer("llama2-chat", "Tell me a joke", nil)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
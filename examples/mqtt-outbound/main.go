@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	spinhttp "github.com/spinframework/spin-go-sdk/v3/http"
 	"github.com/spinframework/spin-go-sdk/v3/mqtt"
@@ -25,7 +26,11 @@ func init() {
 			w.Write([]byte("MQTT_KEEP_ALIVE_INTERVAL is not valid: must be an integer"))
 		}
 
-		conn, err := mqtt.OpenConnection(addr, usr, pass, uint64(keepAlive))
+		conn, err := mqtt.Dial(addr, mqtt.Options{
+			Username:  usr,
+			Password:  pass,
+			KeepAlive: time.Duration(keepAlive) * time.Second,
+		})
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
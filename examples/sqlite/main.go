@@ -5,14 +5,15 @@ import (
 	"net/http"
 
 	spinhttp "github.com/spinframework/spin-go-sdk/v3/http"
+	"github.com/spinframework/spin-go-sdk/v3/spindb"
 	"github.com/spinframework/spin-go-sdk/v3/sqlite"
 )
 
 type Pet struct {
-	ID        int64
-	Name      string
-	Prey      *string // nullable field must be a pointer
-	IsFinicky bool
+	ID        int64   `db:"id"`
+	Name      string  `db:"name"`
+	Prey      *string `db:"prey"` // nullable field must be a pointer
+	IsFinicky bool    `db:"is_finicky"`
 }
 
 func init() {
@@ -26,26 +27,11 @@ func init() {
 			return
 		}
 
-		rows, err := db.Query("SELECT * FROM pets")
+		pets, err := spindb.QueryAll[Pet](r.Context(), db, "SELECT * FROM pets")
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		defer rows.Close()
-
-		var pets []*Pet
-		for rows.Next() {
-			var pet Pet
-			if err := rows.Scan(&pet.ID, &pet.Name, &pet.Prey, &pet.IsFinicky); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			pets = append(pets, &pet)
-		}
-		if err := rows.Err(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
 		if err := json.NewEncoder(w).Encode(pets); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
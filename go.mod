@@ -2,11 +2,13 @@ module github.com/spinframework/spin-go-sdk/v3
 
 go 1.25.5
 
-require go.bytecodealliance.org/pkg v0.2.1
+require (
+	github.com/stretchr/testify v1.11.1
+	go.bytecodealliance.org/pkg v0.2.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.11.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
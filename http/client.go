@@ -59,6 +59,7 @@ func Send(req *http.Request) (*http.Response, error) {
 	}
 
 	toHttpHeader(headers, &resp.Header)
+	body.trailer = &resp.Trailer
 
 	return resp, nil
 }
@@ -1,10 +1,10 @@
 package http
 
 import (
-	"fmt"
 	"net/http"
 
 	wasi "github.com/spinframework/spin-go-sdk/v3/imports/wasi_http_0_3_0_rc_2026_03_15_types"
+	"github.com/spinframework/spin-go-sdk/v3/internal/httpmethod"
 	wit "go.bytecodealliance.org/pkg/wit/types"
 )
 
@@ -12,7 +12,7 @@ import (
 func newHttpRequest(ir *wasi.Request) (*http.Request, error) {
 	defer ir.Drop()
 
-	method, err := methodToString(ir.GetMethod())
+	method, err := httpmethod.FromWasi(ir.GetMethod())
 	if err != nil {
 		return nil, err
 	}
@@ -39,34 +39,17 @@ func newHttpRequest(ir *wasi.Request) (*http.Request, error) {
 
 	toHttpHeader(headers, &req.Header)
 
-	return req, nil
-}
-
-func methodToString(m wasi.Method) (string, error) {
-	switch m.Tag() {
-	case wasi.MethodConnect:
-		return "CONNECT", nil
-	case wasi.MethodDelete:
-		return "DELETE", nil
-	case wasi.MethodGet:
-		return "GET", nil
-	case wasi.MethodHead:
-		return "HEAD", nil
-	case wasi.MethodOptions:
-		return "OPTIONS", nil
-	case wasi.MethodPatch:
-		return "PATCH", nil
-	case wasi.MethodPost:
-		return "POST", nil
-	case wasi.MethodPut:
-		return "PUT", nil
-	case wasi.MethodTrace:
-		return "TRACE", nil
-	case wasi.MethodOther:
-		return m.Other(), fmt.Errorf("unknown http method 'other'")
-	default:
-		return "", fmt.Errorf("failed to convert http method")
+	// As with net/http, declared trailers are present with nil values
+	// until the body has been read to EOF.
+	for _, name := range declaredTrailers(req.Header) {
+		if req.Trailer == nil {
+			req.Trailer = make(http.Header)
+		}
+		req.Trailer[name] = nil
 	}
+	body.trailer = &req.Trailer
+
+	return req, nil
 }
 
 func toHttpHeader(src []wit.Tuple2[string, []uint8], dest *http.Header) {
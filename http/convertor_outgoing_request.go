@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	wasi "github.com/spinframework/spin-go-sdk/v3/imports/wasi_http_0_3_0_rc_2026_03_15_types"
+	"github.com/spinframework/spin-go-sdk/v3/internal/httpmethod"
 	wit "go.bytecodealliance.org/pkg/wit/types"
 )
 
@@ -48,7 +49,7 @@ func newOutgoingHttpRequest(req *http.Request) (*wasi.Request, error) {
 		wit.None[*wasi.RequestOptions](), // TODO: support options
 	)
 	send.Drop()
-	request.SetMethod(toWasiMethod(req.Method))
+	request.SetMethod(httpmethod.ToWasi(req.Method))
 	request.SetAuthority(wit.Some(req.Host))
 	request.SetPathWithQuery(wit.Some(req.URL.Path))
 
@@ -63,28 +64,3 @@ func newOutgoingHttpRequest(req *http.Request) (*wasi.Request, error) {
 
 	return request, nil
 }
-
-func toWasiMethod(s string) wasi.Method {
-	switch s {
-	case http.MethodConnect:
-		return wasi.MakeMethodConnect()
-	case http.MethodDelete:
-		return wasi.MakeMethodDelete()
-	case http.MethodGet:
-		return wasi.MakeMethodGet()
-	case http.MethodHead:
-		return wasi.MakeMethodHead()
-	case http.MethodOptions:
-		return wasi.MakeMethodOptions()
-	case http.MethodPatch:
-		return wasi.MakeMethodPatch()
-	case http.MethodPost:
-		return wasi.MakeMethodPost()
-	case http.MethodPut:
-		return wasi.MakeMethodPut()
-	case http.MethodTrace:
-		return wasi.MakeMethodTrace()
-	default:
-		return wasi.MakeMethodOther(s)
-	}
-}
@@ -1,28 +1,53 @@
 package http
 
 import (
+	"bufio"
 	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	wasi "github.com/spinframework/spin-go-sdk/v3/imports/wasi_http_0_3_0_rc_2026_03_15_types"
 	wit "go.bytecodealliance.org/pkg/wit/types"
 )
 
-// Assert that `responseWriter` implements the required interface
+// Assert that `responseWriter` implements the required interfaces
 var _ http.ResponseWriter = &responseWriter{}
+var _ http.Flusher = &responseWriter{}
+
+// The optional methods looked up by http.ResponseController
+var _ interface{ FlushError() error } = &responseWriter{}
+var _ interface{ SetWriteDeadline(time.Time) error } = &responseWriter{}
+var _ interface{ EnableFullDuplex() error } = &responseWriter{}
 
 type responseWriter struct {
 	// channel to which the response will be sent
 	channel chan wit.Result[*wasi.Response, wasi.ErrorCode]
+	// error from sending the response, returned by later writes
+	sendErr error
+	// whether the request method was HEAD, in which case the body is discarded
+	isHead bool
 	// stream to which the response body is being written
-	stream *wit.StreamWriter[uint8]
-	// future which resolves to an error if there is a problem delivering the response body
-	streamResult *wit.FutureReader[wit.Result[wit.Unit, wasi.ErrorCode]]
-	// headers to send
+	body *bodyWriter
+	// buffer in front of the body; the headers are sent on its first flush
+	buffer *bufio.Writer
+	// deadline applied to body once the response has been sent
+	writeDeadline time.Time
+	// headers set by the handler
 	headers http.Header
+	// headers captured when the status code was written, sent with the response
+	initial http.Header
 	// status code to send
 	statusCode int
+	// whether the status code has been written
+	wroteHeader bool
+	// number of body bytes written by the handler
+	written int64
+	// canonical names of the headers declared in the "Trailer" header
+	declaredTrailers []string
+	// future through which the trailers are sent once the handler returns
 	trailersTx *wit.FutureWriter[wit.Result[wit.Option[*wasi.Fields], wasi.ErrorCode]]
 }
 
@@ -31,60 +56,181 @@ func (self *responseWriter) Header() http.Header {
 }
 
 func (self *responseWriter) Write(buf []byte) (int, error) {
-	err := self.send()
-	if err != nil {
-		return 0, err
+	if !self.wroteHeader {
+		self.WriteHeader(http.StatusOK)
 	}
 
-	count := self.stream.Write(buf)
-
-	if count == 0 && self.stream.ReaderDropped() {
-		return 0, self.takeError()
+	if !bodyAllowedForStatus(self.statusCode) {
+		return 0, http.ErrBodyNotAllowed
 	}
 
-	return int(count), nil
+	self.written += int64(len(buf))
+	return self.buffer.Write(buf)
 }
 
+// WriteHeader records the status code and captures the headers to send with
+// it. As with net/http, it panics if the code is not a valid three-digit
+// status code. Unlike net/http, it also panics if the header has already been
+// written, since the status that was sent can no longer be changed.
+// Informational (1xx) responses cannot be sent through WASI, so they are
+// ignored.
 func (self *responseWriter) WriteHeader(statusCode int) {
+	if statusCode < 100 || statusCode > 999 {
+		panic(fmt.Sprintf("invalid WriteHeader code %v", statusCode))
+	}
+
+	if self.wroteHeader {
+		panic(fmt.Sprintf("http: superfluous WriteHeader call with code %v", statusCode))
+	}
+
+	if statusCode < 200 {
+		return
+	}
+
+	self.wroteHeader = true
 	self.statusCode = statusCode
+	self.initial = self.initialHeaders()
 }
 
+// Flush sends any buffered data to the client, sending the response
+// headers first if they have not been sent yet.
 func (self *responseWriter) Flush() {
+	self.FlushError()
+}
+
+// FlushError is like Flush but returns any error encountered. It is used by
+// http.ResponseController.
+func (self *responseWriter) FlushError() error {
+	if !self.wroteHeader {
+		self.WriteHeader(http.StatusOK)
+	}
+
+	if err := self.buffer.Flush(); err != nil {
+		return err
+	}
+
+	return self.send(nil)
+}
+
+// SetWriteDeadline sets the deadline for writing the response body. Writes
+// to the host stream started after the deadline fail with an error wrapping
+// os.ErrDeadlineExceeded; a write already in progress is not interrupted.
+// A zero value means no deadline.
+func (self *responseWriter) SetWriteDeadline(deadline time.Time) error {
+	self.writeDeadline = deadline
+	if self.body != nil {
+		self.body.deadline = deadline
+	}
+	return nil
 }
 
+// EnableFullDuplex allows the handler to keep reading the request body after
+// it has started writing the response. The request and response bodies are
+// independent WASI streams, so this is always supported.
+func (self *responseWriter) EnableFullDuplex() error {
+	return nil
+}
+
+// writeTrailers resolves the trailers future exactly once. Trailers are the
+// final values of the headers declared in the "Trailer" header, plus any
+// header whose name starts with http.TrailerPrefix.
 func (self *responseWriter) writeTrailers() {
-	if self.trailersTx == nil {
+	trailersTx := self.trailersTx
+	if trailersTx == nil {
 		return
 	}
+	self.trailersTx = nil
 
-	declared := self.headers.Values("Trailer")
 	collected := make(http.Header)
-	for headerName, headerVals := range self.headers {
-		if slices.Contains(declared, headerName) {
-			collected[headerName] = headerVals
+	for _, name := range self.declaredTrailers {
+		if vals, ok := self.headers[name]; ok {
+			collected[name] = vals
+		}
+	}
+	for key, vals := range self.headers {
+		if name, ok := strings.CutPrefix(key, http.TrailerPrefix); ok {
+			name = http.CanonicalHeaderKey(name)
+			collected[name] = append(collected[name], vals...)
 		}
 	}
 
-	if len(collected) > 0 {
-		wasiTrailers, err := toWasiHeaders(collected)
-		if err != nil {
-			errCode := wasi.MakeErrorCodeInternalError(wit.Some(fmt.Sprintf("Cannot send trailers: %v", err)))
-			self.trailersTx.Write(wit.Err[wit.Option[*wasi.Fields]](errCode))
+	if len(collected) == 0 {
+		trailersTx.Write(wit.Ok[wit.Option[*wasi.Fields], wasi.ErrorCode](wit.None[*wasi.Fields]()))
+		return
+	}
+
+	wasiTrailers, err := toWasiHeaders(collected)
+	if err != nil {
+		errCode := wasi.MakeErrorCodeInternalError(wit.Some(fmt.Sprintf("Cannot send trailers: %v", err)))
+		trailersTx.Write(wit.Err[wit.Option[*wasi.Fields]](errCode))
+		return
+	}
+
+	trailersTx.Write(wit.Ok[wit.Option[*wasi.Fields], wasi.ErrorCode](wit.Some(wasiTrailers)))
+}
+
+// initialHeaders returns the headers to send with the response status,
+// recording the declared trailers and leaving them out along with any
+// http.TrailerPrefix headers.
+func (self *responseWriter) initialHeaders() http.Header {
+	self.declaredTrailers = declaredTrailers(self.headers)
+
+	headers := make(http.Header, len(self.headers))
+	for key, vals := range self.headers {
+		if strings.HasPrefix(key, http.TrailerPrefix) || slices.Contains(self.declaredTrailers, key) {
+			continue
 		}
-		self.trailersTx.Write(wit.Ok[wit.Option[*wasi.Fields], wasi.ErrorCode](wit.Some(wasiTrailers)))
-	} else {
-		self.trailersTx.Write(wit.Ok[wit.Option[*wasi.Fields], wasi.ErrorCode](wit.None[*wasi.Fields]()))
+		headers[key] = vals
 	}
+	return headers
+}
 
-	self.trailersTx = nil
+// finish completes the response after the handler has returned. If the
+// whole body is still buffered, Content-Length is set before the headers are
+// sent. The body is then flushed and the trailers are written.
+func (self *responseWriter) finish() error {
+	if !self.wroteHeader {
+		self.WriteHeader(http.StatusOK)
+	}
+
+	if self.channel != nil && self.canSetContentLength() {
+		self.initial.Set("Content-Length", strconv.FormatInt(self.written, 10))
+	}
+
+	if err := self.buffer.Flush(); err != nil {
+		return err
+	}
+
+	if err := self.send(nil); err != nil {
+		return err
+	}
+
+	self.writeTrailers()
+
+	return nil
 }
 
-func (self *responseWriter) close() error {
-	if self.stream != nil {
-		self.stream.Drop()
+func (self *responseWriter) canSetContentLength() bool {
+	if !bodyAllowedForStatus(self.statusCode) || (self.isHead && self.written == 0) {
+		return false
+	}
+	if self.initial.Get("Content-Length") != "" || self.initial.Get("Transfer-Encoding") != "" {
+		return false
 	}
-	if self.streamResult != nil {
-		self.streamResult.Drop()
+	if len(self.declaredTrailers) > 0 {
+		return false
+	}
+	for key := range self.headers {
+		if strings.HasPrefix(key, http.TrailerPrefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *responseWriter) close() error {
+	if self.body != nil {
+		self.body.Close()
 	}
 	if self.trailersTx != nil {
 		self.trailersTx.Drop()
@@ -93,58 +239,125 @@ func (self *responseWriter) close() error {
 	return nil
 }
 
-func (self *responseWriter) send() error {
+// send sends the response status and headers, if not already sent. p is the
+// first chunk of the body, used to sniff the Content-Type if the handler did
+// not set one.
+func (self *responseWriter) send(p []byte) error {
 	channel := self.channel
 
 	if channel == nil {
-		return nil
+		return self.sendErr
 	} else {
 		self.channel = nil
 	}
 
-	fields, err := toWasiHeaders(self.headers)
+	if !self.wroteHeader {
+		self.WriteHeader(http.StatusOK)
+	}
+
+	hasBody := bodyAllowedForStatus(self.statusCode) && !self.isHead
+
+	headers := self.initial
+	if _, haveType := headers["Content-Type"]; !haveType && bodyAllowedForStatus(self.statusCode) && len(p) > 0 {
+		if headers.Get("Content-Encoding") == "" {
+			headers.Set("Content-Type", http.DetectContentType(p))
+		}
+	}
+
+	fields, err := toWasiHeaders(headers)
 	if err != nil {
-		return err
+		return self.fail(channel, err)
 	}
 
-	tx, rx := wasi.MakeStreamU8()
-	self.stream = tx
+	contents := wit.None[*wit.StreamReader[uint8]]()
+	var tx *wit.StreamWriter[uint8]
+	if hasBody {
+		var rx *wit.StreamReader[uint8]
+		tx, rx = wasi.MakeStreamU8()
+		contents = wit.Some(rx)
+	}
 
 	trailersTx, trailersRx := wasi.MakeFutureResultOptionFieldsErrorCode()
 	self.trailersTx = trailersTx
 
 	response, send := wasi.ResponseNew(
 		fields,
-		wit.Some(rx),
+		contents,
 		trailersRx,
 	)
-	self.streamResult = send
 
-	response.SetStatusCode(uint16(self.statusCode))
+	if response.SetStatusCode(uint16(self.statusCode)).IsErr() {
+		response.Drop()
+		send.Drop()
+		if tx != nil {
+			tx.Drop()
+		}
+		return self.fail(channel, fmt.Errorf("invalid status code %d", self.statusCode))
+	}
+
+	if hasBody {
+		self.body = newWriter(tx, send)
+		self.body.deadline = self.writeDeadline
+	} else {
+		send.Drop()
+	}
 
 	channel <- wit.Ok[*wasi.Response, wasi.ErrorCode](response)
 
 	return nil
 }
 
-func (r *responseWriter) takeError() error {
-	if r.streamResult != nil {
-		streamResult := r.streamResult.Read()
-		r.streamResult = nil
-		if streamResult.IsErr() {
-			return fmt.Errorf(
-				"failed to read from HTTP body stream: %s",
-				errorString(streamResult.Err()),
-			)
-		}
+// fail reports err to the host in place of the response.
+func (self *responseWriter) fail(channel chan wit.Result[*wasi.Response, wasi.ErrorCode], err error) error {
+	self.sendErr = err
+	channel <- wit.Err[*wasi.Response, wasi.ErrorCode](
+		wasi.MakeErrorCodeInternalError(wit.Some(fmt.Sprintf(
+			"failed to produce a response: %v\n",
+			err,
+		))),
+	)
+	return err
+}
+
+// chunkWriter sits between the response buffer and the body stream, sending
+// the response headers ahead of the first chunk of the body.
+type chunkWriter struct {
+	res *responseWriter
+}
+
+func (cw chunkWriter) Write(p []byte) (int, error) {
+	if err := cw.res.send(p); err != nil {
+		return 0, err
 	}
-	return nil
+
+	// HEAD requests and bodiless status codes discard the body
+	if cw.res.body == nil {
+		return len(p), nil
+	}
+
+	return cw.res.body.Write(p)
+}
+
+// bodyAllowedForStatus reports whether a response with the given status code
+// may have a body, per RFC 9110.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+	return true
 }
 
 func newHttpResponseWriter() *responseWriter {
-	return &responseWriter{
+	res := &responseWriter{
 		channel:    make(chan wit.Result[*wasi.Response, wasi.ErrorCode]),
 		headers:    http.Header{},
 		statusCode: 200,
 	}
+	res.buffer = bufio.NewWriterSize(chunkWriter{res}, writeBufferSize)
+	return res
 }
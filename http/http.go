@@ -37,9 +37,16 @@ const (
 	HeaderClientAddr = "spin-client-addr"
 )
 
+// DefaultWriteBufferSize is the default size of the buffer in front of the
+// response body stream.
+const DefaultWriteBufferSize = 4096
+
 // the function that will be called by the http trigger in Spin.
 var handlerFn = defaultHandler
 
+// the size of the response body buffer.
+var writeBufferSize = DefaultWriteBufferSize
+
 // defaultHandler is a placeholder for returning a useful error to stderr when
 // the handler is not set.
 var defaultHandler = func(http.ResponseWriter, *http.Request) {
@@ -52,6 +59,18 @@ func Handle(fn func(http.ResponseWriter, *http.Request)) {
 	handlerFn = fn
 }
 
+// SetWriteBufferSize sets the size of the buffer used for response bodies.
+// Writes smaller than the buffer are coalesced and sent to the host when the
+// buffer fills, when the handler calls Flush, or when the handler returns.
+// A size of zero or less restores DefaultWriteBufferSize.
+// It must be called in an init() function.
+func SetWriteBufferSize(size int) {
+	if size <= 0 {
+		size = DefaultWriteBufferSize
+	}
+	writeBufferSize = size
+}
+
 var wasiHandle = func(request *wasi.Request) wit.Result[*wasi.Response, wasi.ErrorCode] {
 	httpRes := newHttpResponseWriter()
 
@@ -70,21 +89,16 @@ var wasiHandle = func(request *wasi.Request) wit.Result[*wasi.Response, wasi.Err
 		} else {
 			defer httpReq.Body.Close()
 
+			httpRes.isHead = httpReq.Method == http.MethodHead
+
 			// run the user's handler
 			handlerFn(httpRes, httpReq)
 
-			// if the user's handler never sent a response, we'll
-			// send a default one here:
-			if err := httpRes.send(); err != nil {
-				httpRes.channel <- wit.Err[*wasi.Response, wasi.ErrorCode](
-					wasi.MakeErrorCodeInternalError(wit.Some(fmt.Sprintf(
-						"failed to produce a response: %v\n",
-						err,
-					))),
-				)
+			// send the response, if the user's handler didn't flush it,
+			// followed by any buffered body and the trailers
+			if err := httpRes.finish(); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to finish response: %v\n", err)
 			}
-
-			httpRes.writeTrailers()
 		}
 	}()
 
@@ -122,6 +136,20 @@ func toWasiHeaders(headers http.Header) (*wasi.Fields, error) {
 	return fields, nil
 }
 
+// declaredTrailers returns the canonical header names listed in the
+// "Trailer" header.
+func declaredTrailers(headers http.Header) []string {
+	var names []string
+	for _, val := range headers.Values("Trailer") {
+		for name := range strings.SplitSeq(val, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
 func trailersFuture() *wit.FutureReader[wit.Result[wit.Option[*wasi.Fields], wasi.ErrorCode]] {
 	tx, rx := wasi.MakeFutureResultOptionFieldsErrorCode()
 	go tx.Write(wit.Ok[wit.Option[*wasi.Fields], wasi.ErrorCode](wit.None[*wasi.Fields]()))
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event is a single Server-Sent Event.
+type Event struct {
+	// ID sets the event ID, sent as the "id" field.
+	ID string
+	// Type is the event type, sent as the "event" field. Clients treat
+	// events without a type as "message" events.
+	Type string
+	// Data is the event payload. Multi-line data is sent as one "data"
+	// field per line.
+	Data string
+	// Retry tells the client how long to wait before reconnecting. It is
+	// omitted if zero.
+	Retry time.Duration
+}
+
+// EventStream writes Server-Sent Events to an HTTP response, flushing
+// each event to the client as soon as it is written.
+type EventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// NewEventStream prepares w for sending Server-Sent Events. It sets the
+// Content-Type and Cache-Control headers, unless the handler already set
+// them, and sends the response headers to the client.
+func NewEventStream(w http.ResponseWriter) (*EventStream, error) {
+	header := w.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "text/event-stream")
+	}
+	if header.Get("Cache-Control") == "" {
+		header.Set("Cache-Control", "no-cache")
+	}
+
+	s := &EventStream{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+	if err := s.rc.Flush(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Send writes an event and flushes it to the client.
+func (s *EventStream) Send(ev Event) error {
+	if strings.ContainsAny(ev.ID, "\r\n") {
+		return errors.New("sse: event ID must not contain a newline")
+	}
+	if strings.ContainsAny(ev.Type, "\r\n") {
+		return errors.New("sse: event type must not contain a newline")
+	}
+
+	var b strings.Builder
+	if ev.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", ev.ID)
+	}
+	if ev.Type != "" {
+		fmt.Fprintf(&b, "event: %s\n", ev.Type)
+	}
+	if ev.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+	for _, line := range splitLines(ev.Data) {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// Comment writes a comment line, which clients ignore. It is commonly used
+// as a keep-alive.
+func (s *EventStream) Comment(text string) error {
+	var b strings.Builder
+	for _, line := range splitLines(text) {
+		fmt.Fprintf(&b, ": %s\n", line)
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// SendText sends each chunk of text yielded by seq, such as the tokens of
+// llm.InferStream, as the data of a message event, and then sends a "done"
+// event. If seq yields an error, SendText sends it as an "error" event, whose
+// data is the error message, and returns it:
+//
+//	stream, err := spinhttp.NewEventStream(w)
+//	if err != nil {
+//		return
+//	}
+//	stream.SendText(llm.InferStream(r.Context(), "llama2-chat", prompt, nil))
+//
+// Empty chunks are skipped. If sending fails, for example because the client
+// has gone away, SendText stops consuming seq and returns the error.
+func (s *EventStream) SendText(seq iter.Seq2[string, error]) error {
+	for text, err := range seq {
+		if err != nil {
+			if sendErr := s.Send(Event{Type: "error", Data: err.Error()}); sendErr != nil {
+				return errors.Join(err, sendErr)
+			}
+			return err
+		}
+		if text == "" {
+			continue
+		}
+		if err := s.Send(Event{Data: text}); err != nil {
+			return err
+		}
+	}
+	return s.Send(Event{Type: "done"})
+}
+
+func (s *EventStream) write(data string) error {
+	if _, err := s.w.Write([]byte(data)); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// splitLines splits text on any of the line endings allowed by the event
+// stream format.
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.Split(text, "\n")
+}
@@ -3,18 +3,24 @@ package http
 import (
 	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"time"
 
 	wasi "github.com/spinframework/spin-go-sdk/v3/imports/wasi_http_0_3_0_rc_2026_03_15_types"
 	wit "go.bytecodealliance.org/pkg/wit/types"
 )
 
-// Assert `bodyReader` implements the required interfaces
+// Assert `bodyReader` and `bodyWriter` implement the required interfaces
 var _ io.Reader = &bodyReader{}
 var _ io.ReadCloser = &bodyReader{}
+var _ io.WriteCloser = &bodyWriter{}
 
 type bodyReader struct {
 	stream   *wit.StreamReader[uint8]
 	trailers *wit.FutureReader[wit.Result[wit.Option[*wasi.Fields], wasi.ErrorCode]]
+	// header that receives the trailers once the body reaches EOF
+	trailer *http.Header
 }
 
 func (self *bodyReader) Close() error {
@@ -47,10 +53,24 @@ func (self *bodyReader) takeError() error {
 		if trailers.IsErr() {
 			return fmt.Errorf("failed to read from HTTP body stream: %s", errorString(trailers.Err()))
 		}
+		if fields := trailers.Ok(); fields.IsSome() {
+			self.setTrailer(fields.Some())
+		}
 	}
 	return io.EOF
 }
 
+func (self *bodyReader) setTrailer(fields *wasi.Fields) {
+	defer fields.Drop()
+	if self.trailer == nil {
+		return
+	}
+	if *self.trailer == nil {
+		*self.trailer = make(http.Header)
+	}
+	toHttpHeader(fields.CopyAll(), self.trailer)
+}
+
 // create an io.Reader from the input stream
 func newReader(
 	stream *wit.StreamReader[uint8],
@@ -61,3 +81,59 @@ func newReader(
 		trailers: trailers,
 	}
 }
+
+type bodyWriter struct {
+	stream *wit.StreamWriter[uint8]
+	// future which resolves to an error if there is a problem delivering the body
+	result *wit.FutureReader[wit.Result[wit.Unit, wasi.ErrorCode]]
+	// writes started after the deadline fail with os.ErrDeadlineExceeded
+	deadline time.Time
+}
+
+func (self *bodyWriter) Close() error {
+	if self.stream != nil {
+		self.stream.Drop()
+	}
+	if self.result != nil {
+		self.result.Drop()
+	}
+	return nil
+}
+
+func (self *bodyWriter) Write(p []byte) (n int, err error) {
+	if !self.deadline.IsZero() && time.Now().After(self.deadline) {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	count := self.stream.WriteAll(p)
+	if int(count) < len(p) {
+		return int(count), self.takeError()
+	}
+
+	return int(count), nil
+}
+
+func (self *bodyWriter) takeError() error {
+	if self.result != nil {
+		result := self.result.Read()
+		self.result = nil
+		if result.IsErr() {
+			return fmt.Errorf(
+				"failed to write to HTTP body stream: %s",
+				errorString(result.Err()),
+			)
+		}
+	}
+	return io.ErrClosedPipe
+}
+
+// create an io.Writer from the output stream
+func newWriter(
+	stream *wit.StreamWriter[uint8],
+	result *wit.FutureReader[wit.Result[wit.Unit, wasi.ErrorCode]],
+) *bodyWriter {
+	return &bodyWriter{
+		stream: stream,
+		result: result,
+	}
+}
@@ -144,6 +144,118 @@ func TestHTTPTrigger(t *testing.T) {
 	}
 }
 
+func TestHTTPResponse(t *testing.T) {
+	spin := startSpin(t, "testdata/http-response")
+	defer spin.cancel()
+
+	// Wait for the app to come up.
+	retryGet(t, spin.url+"/small").Body.Close()
+
+	do := func(t *testing.T, method, path string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, spin.url+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(b)
+	}
+
+	t.Run("content length", func(t *testing.T) {
+		resp, body := do(t, http.MethodGet, "/small")
+		if body != "hello" {
+			t.Fatalf("body is not equal: want = %q got = %q", "hello", body)
+		}
+		if resp.ContentLength != 5 {
+			t.Fatalf("Content-Length: want = 5 got = %d", resp.ContentLength)
+		}
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		resp, body := do(t, http.MethodGet, "/large")
+		if len(body) != 8192 {
+			t.Fatalf("body length: want = 8192 got = %d", len(body))
+		}
+		if resp.ContentLength != -1 {
+			t.Fatalf("Content-Length: want unset got = %d", resp.ContentLength)
+		}
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		resp, body := do(t, http.MethodGet, "/flush")
+		if body != "first,second" {
+			t.Fatalf("body is not equal: want = %q got = %q", "first,second", body)
+		}
+		if resp.ContentLength != -1 {
+			t.Fatalf("Content-Length: want unset got = %d", resp.ContentLength)
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		resp, body := do(t, http.MethodHead, "/small")
+		if body != "" {
+			t.Fatalf("HEAD response has a body: %q", body)
+		}
+		if got := resp.Header.Get("Content-Length"); got != "5" {
+			t.Fatalf("Content-Length: want = 5 got = %q", got)
+		}
+	})
+
+	for path, status := range map[string]int{
+		"/no-content":   http.StatusNoContent,
+		"/not-modified": http.StatusNotModified,
+	} {
+		t.Run(path, func(t *testing.T) {
+			resp, body := do(t, http.MethodGet, path)
+			if resp.StatusCode != status {
+				t.Fatalf("unexpected status: %v", resp.Status)
+			}
+			if body != "" {
+				t.Fatalf("body should be empty: %q", body)
+			}
+		})
+	}
+
+	t.Run("trailers", func(t *testing.T) {
+		resp, body := do(t, http.MethodGet, "/trailers")
+		if body != "body" {
+			t.Fatalf("body is not equal: want = %q got = %q", "body", body)
+		}
+		if resp.ContentLength != -1 {
+			t.Fatalf("Content-Length: want unset got = %d", resp.ContentLength)
+		}
+		if got := resp.Trailer.Get("X-Checksum"); got != "declared" {
+			t.Fatalf("trailer X-Checksum: want = %q got = %q", "declared", got)
+		}
+	})
+
+	t.Run("events", func(t *testing.T) {
+		resp, body := do(t, http.MethodGet, "/events")
+		if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+			t.Fatalf("Content-Type: want = text/event-stream got = %q", got)
+		}
+		want := "id: 1\nevent: greeting\ndata: hello\ndata: world\n\n: keep-alive\n\ndata: bye\n\n"
+		if body != want {
+			t.Fatalf("body is not equal: want = %q got = %q", want, body)
+		}
+	})
+
+	t.Run("duplicate WriteHeader", func(t *testing.T) {
+		resp, _ := do(t, http.MethodGet, "/duplicate-header")
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("unexpected status: %v", resp.Status)
+		}
+	})
+}
+
 func TestKeyValue(t *testing.T) {
 	spin := startSpin(t, "testdata/key-value")
 	defer spin.cancel()
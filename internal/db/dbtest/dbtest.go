@@ -0,0 +1,97 @@
+// Package dbtest provides an in-memory database/sql driver for testing code
+// that runs queries, without a Spin host.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// Conn is a driver connection whose statements are answered by Query and
+// Exec. A nil function fails the statements it would answer.
+type Conn struct {
+	Query func(query string, args []driver.NamedValue) (driver.Rows, error)
+	Exec  func(query string, args []driver.NamedValue) (driver.Result, error)
+}
+
+var _ driver.QueryerContext = (*Conn)(nil)
+var _ driver.ExecerContext = (*Conn)(nil)
+
+// Open returns a database whose connections are all conn. It is closed when
+// the test finishes.
+func Open(t testing.TB, conn *Conn) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(connector{conn})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (c *Conn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (c *Conn) Close() error                        { return nil }
+func (c *Conn) Begin() (driver.Tx, error)           { return nil, errors.New("not implemented") }
+
+func (c *Conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.Query == nil {
+		return nil, errors.New("unexpected query")
+	}
+	return c.Query(query, args)
+}
+
+func (c *Conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.Exec == nil {
+		return nil, errors.New("unexpected statement")
+	}
+	return c.Exec(query, args)
+}
+
+type connector struct {
+	conn *Conn
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c connector) Driver() driver.Driver                        { return nil }
+
+// Rows is a result set held in memory.
+type Rows struct {
+	Columns []string
+	// Types are the scan types of the columns. If nil, every column is
+	// reported as any.
+	Types  []reflect.Type
+	Values [][]driver.Value
+}
+
+// Iter returns driver rows that iterate over r.
+func (r *Rows) Iter() driver.Rows {
+	return &rows{Rows: r}
+}
+
+type rows struct {
+	*Rows
+	next int
+}
+
+var _ driver.RowsColumnTypeScanType = (*rows)(nil)
+
+func (r *rows) Columns() []string { return r.Rows.Columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next == len(r.Values) {
+		return io.EOF
+	}
+	copy(dest, r.Values[r.next])
+	r.next++
+	return nil
+}
+
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if r.Types == nil {
+		return reflect.TypeFor[any]()
+	}
+	return r.Types[index]
+}
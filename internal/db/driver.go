@@ -1,16 +1,169 @@
 // Package db provides shared database utilities for Spin database drivers.
 package db
 
-import "database/sql/driver"
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+)
 
-// GlobalParameterConverter is a global valueConverter instance to convert parameters.
-var GlobalParameterConverter = &valueConverter{}
+// ConversionError is returned when a query argument cannot be converted to a
+// value supported by the database driver.
+type ConversionError struct {
+	// Ordinal is the 1-based position of the argument, or 0 if unknown.
+	Ordinal int
+	// Name is the name of the argument if it was passed with sql.Named.
+	Name string
+	// Value is the argument as it was passed to the driver.
+	Value any
+	// Err is the reason the argument could not be converted, or nil if its
+	// type is unsupported.
+	Err error
+}
+
+func (e *ConversionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("cannot convert %T: %v", e.Value, e.Err)
+	}
+	return fmt.Sprintf("unsupported type %T", e.Value)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// CheckNamedValue replaces nv.Value with the result of convert, returning a
+// *ConversionError describing the argument if the conversion fails. Drivers
+// use it to implement driver.NamedValueChecker.
+func CheckNamedValue[T any](nv *driver.NamedValue, convert func(any) (T, error)) error {
+	v, err := convert(nv.Value)
+	if err != nil {
+		var convErr *ConversionError
+		if !errors.As(err, &convErr) {
+			convErr = &ConversionError{Err: err}
+		}
+		convErr.Ordinal = nv.Ordinal
+		convErr.Name = nv.Name
+		convErr.Value = nv.Value
+		return convErr
+	}
+
+	nv.Value = v
+	return nil
+}
+
+// NamedValues returns the values of args, in order.
+func NamedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+var valuerType = reflect.TypeFor[driver.Valuer]()
+
+// Indirect simplifies x into a value a driver is more likely to convert:
+// driver.Valuer implementations are called, pointers are dereferenced with nil
+// pointers becoming nil, and named basic types are converted to the
+// corresponding predeclared type. It reports false if x cannot be simplified
+// any further.
+func Indirect(x any) (any, bool, error) {
+	if x == nil {
+		return nil, false, nil
+	}
+
+	rv := reflect.ValueOf(x)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, true, nil
+		}
+		// Dereference first so the driver sees its own types behind pointers,
+		// unless only the pointer knows how to produce a value.
+		if _, ok := x.(driver.Valuer); !ok || rv.Type().Elem().Implements(valuerType) {
+			return rv.Elem().Interface(), true, nil
+		}
+	}
+
+	var v any
+	if vr, ok := x.(driver.Valuer); ok {
+		var err error
+		if v, err = vr.Value(); err != nil {
+			return nil, false, err
+		}
+	} else {
+		switch rv.Kind() {
+		case reflect.Bool:
+			v = rv.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v = rv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v = rv.Uint()
+		case reflect.Float32, reflect.Float64:
+			v = rv.Float()
+		case reflect.String:
+			v = rv.String()
+		case reflect.Slice:
+			if rv.Type().Elem().Kind() != reflect.Uint8 {
+				return nil, false, nil
+			}
+			v = rv.Bytes()
+		default:
+			return nil, false, nil
+		}
+	}
+
+	// Stop when nothing changed, so that callers can recurse safely.
+	if v != nil && reflect.TypeOf(v) == rv.Type() {
+		return nil, false, nil
+	}
+
+	return v, true, nil
+}
+
+// Row is a row read from a host stream, or the error that ended the stream.
+type Row struct {
+	Values []any
+	Err    error
+}
 
-var _ driver.ValueConverter = (*valueConverter)(nil)
+// Await calls fn on a new goroutine and waits for it to return or for ctx to
+// be done, whichever happens first. If ctx is done first, Await returns
+// ctx.Err() and release is called with the result of fn once it returns.
+// release must drop every handle in that result, and the caller must leave
+// any handle fn is still reading to fn and release, since dropping it during
+// a pending read traps.
+//
+// The host operation itself is not cancelled: the bindings do not expose
+// subtask cancellation, and a FutureReader or StreamReader cannot cancel a
+// pending read. Dropping the handles once fn returns is what tells the host
+// to stop producing results.
+//
+// This relies on fn blocking in an async host call, which lets other
+// goroutines run while it waits.
+func Await[T any](ctx context.Context, fn func() T, release func(T)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if ctx.Done() == nil {
+		return fn(), nil
+	}
 
-// valueConverter is a no-op value converter.
-type valueConverter struct{}
+	ch := make(chan T, 1)
+	go func() {
+		ch <- fn()
+	}()
 
-func (c *valueConverter) ConvertValue(v any) (driver.Value, error) {
-	return driver.Value(v), nil
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		go func() {
+			release(<-ch)
+		}()
+		return zero, ctx.Err()
+	}
 }
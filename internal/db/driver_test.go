@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwait(t *testing.T) {
+	t.Run("returns result", func(t *testing.T) {
+		got, err := Await(context.Background(), func() int { return 42 }, func(int) {
+			t.Error("release called for a completed call")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 42, got)
+	})
+
+	t.Run("already canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Await(ctx, func() int {
+			t.Error("fn called with a canceled context")
+			return 0
+		}, func(int) {})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		unblock := make(chan struct{})
+		released := make(chan int, 1)
+
+		go cancel()
+		_, err := Await(ctx, func() int {
+			<-unblock
+			return 7
+		}, func(v int) {
+			released <- v
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(unblock)
+		assert.Equal(t, 7, <-released)
+	})
+}
@@ -0,0 +1,118 @@
+package db
+
+import "strings"
+
+// Dialect selects the SQL syntax recognized by Skip.
+type Dialect int
+
+const (
+	// Postgres is the PostgreSQL syntax: '' strings, E'' escape strings with
+	// backslash escapes, "" identifiers, dollar-quoted strings, "--" comments
+	// and nesting "/* */" comments.
+	Postgres Dialect = iota
+	// SQLite is the SQLite syntax: '' strings, "", `` and [] identifiers,
+	// "--" comments and "/* */" comments, which do not nest.
+	SQLite
+)
+
+// Skip returns the index after the string literal, quoted identifier or
+// comment that starts at query[i], or i if none starts there. Tokens that are
+// not closed extend to the end of query.
+//
+// Callers must not call Skip inside a word, so that the "E" of an escape
+// string is only recognized at the start of one and, for PostgreSQL, a "$" in
+// an identifier is not taken for a dollar quote.
+func (d Dialect) Skip(query string, i int) int {
+	switch c := query[i]; {
+	case c == '\'' || c == '"':
+		return skipQuoted(query, i, c, false)
+	case strings.HasPrefix(query[i:], "--"):
+		if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+			return i + end + 1
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "/*"):
+		return skipComment(query, i, d == Postgres)
+	}
+
+	switch d {
+	case Postgres:
+		switch c := query[i]; {
+		case (c == 'E' || c == 'e') && i+1 < len(query) && query[i+1] == '\'':
+			return skipQuoted(query, i+1, '\'', true)
+		case c == '$':
+			return skipDollarQuoted(query, i)
+		}
+	case SQLite:
+		switch c := query[i]; c {
+		case '`':
+			return skipQuoted(query, i, c, false)
+		case '[':
+			return skipQuoted(query, i, ']', false)
+		}
+	}
+	return i
+}
+
+// skipQuoted returns the index after the quoted token starting at i, which is
+// closed by end. A doubled closing character is an escaped one, as is one
+// preceded by a backslash if escapes is set.
+func skipQuoted(query string, i int, end byte, escapes bool) int {
+	for i++; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case end:
+			if i+1 < len(query) && query[i+1] == end {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// skipComment returns the index after the "/* */" comment starting at i.
+func skipComment(query string, i int, nested bool) int {
+	depth := 0
+	for i < len(query) {
+		switch {
+		case strings.HasPrefix(query[i:], "/*") && (nested || depth == 0):
+			depth++
+			i += 2
+		case strings.HasPrefix(query[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(query)
+}
+
+// skipDollarQuoted returns the index after the dollar-quoted string starting
+// at i, or i if there is none, as for a "$n" parameter.
+func skipDollarQuoted(query string, i int) int {
+	j := i + 1
+	for j < len(query) && isIdentChar(query[j]) {
+		j++
+	}
+	if j == len(query) || query[j] != '$' || (j > i+1 && query[i+1] >= '0' && query[i+1] <= '9') {
+		return i
+	}
+	tag := query[i : j+1]
+	if end := strings.Index(query[j+1:], tag); end >= 0 {
+		return j + 1 + end + len(tag)
+	}
+	return len(query)
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
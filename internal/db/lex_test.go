@@ -0,0 +1,105 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkip(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		query   string
+		i       int
+		want    int
+	}{{
+		name:  "string",
+		query: `'it''s' x`,
+		want:  7,
+	}, {
+		name:  "backslash in string",
+		query: `'a\' x`,
+		want:  4,
+	}, {
+		name:  "escape string",
+		query: `E'it\'s' x`,
+		want:  8,
+	}, {
+		name:  "lowercase escape string",
+		query: `e'\\' x`,
+		want:  5,
+	}, {
+		name:  "identifier",
+		query: `"a""b" x`,
+		want:  6,
+	}, {
+		name:  "line comment",
+		query: "-- a\nx",
+		want:  5,
+	}, {
+		name:  "nested comment",
+		query: "/* a /* b */ c */ x",
+		want:  17,
+	}, {
+		name:  "dollar quoted",
+		query: "$tag$ $$ $tag$ x",
+		want:  14,
+	}, {
+		name:  "empty dollar tag",
+		query: "$$ a $$ x",
+		want:  7,
+	}, {
+		name:  "parameter",
+		query: "$1 x",
+		want:  0,
+	}, {
+		name:  "backtick in Postgres",
+		query: "`a` x",
+		want:  0,
+	}, {
+		name:  "unterminated",
+		query: "'abc",
+		want:  4,
+	}, {
+		name:  "word",
+		query: "SELECT",
+		want:  0,
+	}, {
+		name:    "SQLite backticks",
+		dialect: SQLite,
+		query:   "`a``b` x",
+		want:    6,
+	}, {
+		name:    "SQLite brackets",
+		dialect: SQLite,
+		query:   "[a;b] x",
+		want:    5,
+	}, {
+		name:    "SQLite comments do not nest",
+		dialect: SQLite,
+		query:   "/* a /* b */ c */ x",
+		want:    12,
+	}, {
+		name:    "SQLite has no escape strings",
+		dialect: SQLite,
+		query:   `E'a\' x`,
+		want:    0,
+	}, {
+		name:    "SQLite has no dollar quotes",
+		dialect: SQLite,
+		query:   "$a$ x",
+		want:    0,
+	}, {
+		name:  "offset",
+		query: "x 'a' y",
+		i:     2,
+		want:  5,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.dialect.Skip(tt.query, tt.i))
+		})
+	}
+}
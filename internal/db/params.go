@@ -0,0 +1,72 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// BindArgs orders args to match the parameters of a statement. params holds
+// the name of each parameter in position order, or "" for a positional
+// parameter.
+//
+// Named arguments are bound to the parameter with the same name. The remaining
+// arguments are bound in order to the parameters not claimed by name, so that
+// named parameters may also be bound by position. An error is returned unless
+// every parameter is bound by exactly one argument.
+func BindArgs(params []string, args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(params))
+	bound := make([]bool, len(params))
+
+	var positional []driver.NamedValue
+	for _, arg := range args {
+		if arg.Name == "" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		found := false
+		for i, name := range params {
+			if name == arg.Name {
+				values[i] = arg.Value
+				bound[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no parameter named %q in statement", arg.Name)
+		}
+	}
+
+	next := 0
+	for _, arg := range positional {
+		for next < len(params) && bound[next] {
+			next++
+		}
+		if next == len(params) {
+			return nil, fmt.Errorf("expected %d arguments, got %d", len(params), len(args))
+		}
+		values[next] = arg.Value
+		bound[next] = true
+	}
+
+	for i, ok := range bound {
+		if !ok {
+			if params[i] != "" {
+				return nil, fmt.Errorf("missing argument for parameter %q", params[i])
+			}
+			return nil, fmt.Errorf("missing argument for parameter %d", i+1)
+		}
+	}
+
+	return values, nil
+}
+
+// ToNamedValues converts positional arguments, as passed to the legacy
+// driver.Stmt methods, to unnamed driver.NamedValues.
+func ToNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
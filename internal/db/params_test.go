@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []string
+		args    []driver.NamedValue
+		want    []driver.Value
+		wantErr string
+	}{{
+		name:   "positional",
+		params: []string{"", ""},
+		args:   []driver.NamedValue{{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: "a"}},
+		want:   []driver.Value{1, "a"},
+	}, {
+		name:   "named out of order",
+		params: []string{"id", "name"},
+		args:   []driver.NamedValue{{Name: "name", Ordinal: 1, Value: "a"}, {Name: "id", Ordinal: 2, Value: 1}},
+		want:   []driver.Value{1, "a"},
+	}, {
+		name:   "named by position",
+		params: []string{"id", "name"},
+		args:   []driver.NamedValue{{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: "a"}},
+		want:   []driver.Value{1, "a"},
+	}, {
+		name:   "mixed",
+		params: []string{"", "id", ""},
+		args:   []driver.NamedValue{{Name: "id", Ordinal: 1, Value: 1}, {Ordinal: 2, Value: "a"}, {Ordinal: 3, Value: "b"}},
+		want:   []driver.Value{"a", 1, "b"},
+	}, {
+		name:    "unknown name",
+		params:  []string{"id"},
+		args:    []driver.NamedValue{{Name: "other", Ordinal: 1, Value: 1}},
+		wantErr: `no parameter named "other" in statement`,
+	}, {
+		name:    "too many",
+		params:  []string{""},
+		args:    []driver.NamedValue{{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: 2}},
+		wantErr: "expected 1 arguments, got 2",
+	}, {
+		name:    "missing named",
+		params:  []string{"id", "name"},
+		args:    []driver.NamedValue{{Name: "id", Ordinal: 1, Value: 1}},
+		wantErr: `missing argument for parameter "name"`,
+	}, {
+		name:    "missing positional",
+		params:  []string{"", ""},
+		args:    []driver.NamedValue{{Ordinal: 1, Value: 1}},
+		wantErr: "missing argument for parameter 2",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BindArgs(tt.params, tt.args)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package httpmethod converts HTTP methods between net/http and wasi:http.
+// It is separate from package http so that it can be tested on the host,
+// where the rest of the wasi:http bindings cannot be linked.
+package httpmethod
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	wasi "github.com/spinframework/spin-go-sdk/v3/imports/wasi_http_0_3_0_rc_2026_03_15_types"
+)
+
+// FromWasi returns the name of m. Extension methods must be valid tokens.
+func FromWasi(m wasi.Method) (string, error) {
+	switch m.Tag() {
+	case wasi.MethodConnect:
+		return "CONNECT", nil
+	case wasi.MethodDelete:
+		return "DELETE", nil
+	case wasi.MethodGet:
+		return "GET", nil
+	case wasi.MethodHead:
+		return "HEAD", nil
+	case wasi.MethodOptions:
+		return "OPTIONS", nil
+	case wasi.MethodPatch:
+		return "PATCH", nil
+	case wasi.MethodPost:
+		return "POST", nil
+	case wasi.MethodPut:
+		return "PUT", nil
+	case wasi.MethodTrace:
+		return "TRACE", nil
+	case wasi.MethodOther:
+		// Extension methods such as WebDAV's PROPFIND are passed through as-is.
+		method := m.Other()
+		if !isToken(method) {
+			return "", fmt.Errorf("invalid http method %q", method)
+		}
+		return method, nil
+	default:
+		return "", fmt.Errorf("failed to convert http method")
+	}
+}
+
+// isToken reports whether s is a valid token, as defined by RFC 9110 section 5.6.2.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range []byte(s) {
+		if !isTokenChar(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTokenChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+}
+
+// ToWasi returns the method named s.
+func ToWasi(s string) wasi.Method {
+	switch s {
+	case http.MethodConnect:
+		return wasi.MakeMethodConnect()
+	case http.MethodDelete:
+		return wasi.MakeMethodDelete()
+	case http.MethodGet:
+		return wasi.MakeMethodGet()
+	case http.MethodHead:
+		return wasi.MakeMethodHead()
+	case http.MethodOptions:
+		return wasi.MakeMethodOptions()
+	case http.MethodPatch:
+		return wasi.MakeMethodPatch()
+	case http.MethodPost:
+		return wasi.MakeMethodPost()
+	case http.MethodPut:
+		return wasi.MakeMethodPut()
+	case http.MethodTrace:
+		return wasi.MakeMethodTrace()
+	default:
+		return wasi.MakeMethodOther(s)
+	}
+}
@@ -0,0 +1,99 @@
+package httpmethod
+
+import (
+	"net/http"
+	"testing"
+
+	wasi "github.com/spinframework/spin-go-sdk/v3/imports/wasi_http_0_3_0_rc_2026_03_15_types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var methodTests = []struct {
+	name   string
+	method wasi.Method
+	want   string
+}{{
+	name:   "CONNECT",
+	method: wasi.MakeMethodConnect(),
+	want:   http.MethodConnect,
+}, {
+	name:   "DELETE",
+	method: wasi.MakeMethodDelete(),
+	want:   http.MethodDelete,
+}, {
+	name:   "GET",
+	method: wasi.MakeMethodGet(),
+	want:   http.MethodGet,
+}, {
+	name:   "HEAD",
+	method: wasi.MakeMethodHead(),
+	want:   http.MethodHead,
+}, {
+	name:   "OPTIONS",
+	method: wasi.MakeMethodOptions(),
+	want:   http.MethodOptions,
+}, {
+	name:   "PATCH",
+	method: wasi.MakeMethodPatch(),
+	want:   http.MethodPatch,
+}, {
+	name:   "POST",
+	method: wasi.MakeMethodPost(),
+	want:   http.MethodPost,
+}, {
+	name:   "PUT",
+	method: wasi.MakeMethodPut(),
+	want:   http.MethodPut,
+}, {
+	name:   "TRACE",
+	method: wasi.MakeMethodTrace(),
+	want:   http.MethodTrace,
+}, {
+	name:   "Other/PROPFIND",
+	method: wasi.MakeMethodOther("PROPFIND"),
+	want:   "PROPFIND",
+}, {
+	name:   "Other/MKCOL",
+	method: wasi.MakeMethodOther("MKCOL"),
+	want:   "MKCOL",
+}, {
+	name:   "Other/PURGE",
+	method: wasi.MakeMethodOther("PURGE"),
+	want:   "PURGE",
+}, {
+	name:   "Other/custom",
+	method: wasi.MakeMethodOther("X-CUSTOM_1"),
+	want:   "X-CUSTOM_1",
+}}
+
+func TestFromWasi(t *testing.T) {
+	for _, tt := range methodTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromWasi(tt.method)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromWasi_InvalidOther(t *testing.T) {
+	for _, method := range []string{"", "GET /", "PROP FIND", "BAD\r\n", "TAB\t", "QUOTE\"", "UTF8é"} {
+		t.Run(method, func(t *testing.T) {
+			_, err := FromWasi(wasi.MakeMethodOther(method))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestToWasi(t *testing.T) {
+	for _, tt := range methodTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToWasi(tt.want)
+			assert.Equal(t, tt.method.Tag(), got.Tag())
+			if got.Tag() == wasi.MethodOther {
+				assert.Equal(t, tt.method.Other(), got.Other())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package redistrigger adapts Go message handlers to the exports of the Spin
+// Redis trigger worlds, so that the current and legacy worlds translate
+// payloads and errors the same way.
+package redistrigger
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+
+	redis_types "github.com/spinframework/spin-go-sdk/v3/imports/fermyon_spin_redis_types"
+	redis "github.com/spinframework/spin-go-sdk/v3/imports/spin_redis_3_0_0_redis"
+	wit "go.bytecodealliance.org/pkg/wit/types"
+)
+
+// Call calls handle with message, returning a panic in handle as an error
+// that carries the panic value and the stack trace of the panicking goroutine.
+func Call(handle func(message []byte) error, message []byte) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("redis: handler panicked: %v\n%s", p, debug.Stack())
+		}
+	}()
+	return handle(message)
+}
+
+// Handler adapts handle to the spin:redis/inbound-redis@3.0.0 export, which
+// reports errors as "other" errors with their message.
+func Handler(handle func(message []byte) error) func(message []byte) wit.Result[wit.Unit, redis.Error] {
+	return func(message []byte) wit.Result[wit.Unit, redis.Error] {
+		if err := Call(handle, message); err != nil {
+			return wit.Err[wit.Unit](redis.MakeErrorOther(err.Error()))
+		}
+		return wit.Ok[wit.Unit, redis.Error](wit.Unit{})
+	}
+}
+
+// LegacyHandler adapts handle to the fermyon:spin/inbound-redis export. That
+// interface's error carries no message, so errors are written to log, which
+// is normally os.Stderr, where Spin records them.
+func LegacyHandler(handle func(message []byte) error, log io.Writer) func(message []byte) wit.Result[wit.Unit, redis_types.Error] {
+	return func(message []byte) wit.Result[wit.Unit, redis_types.Error] {
+		if err := Call(handle, message); err != nil {
+			fmt.Fprintln(log, err)
+			return wit.Err[wit.Unit](redis_types.ErrorError)
+		}
+		return wit.Ok[wit.Unit, redis_types.Error](wit.Unit{})
+	}
+}
@@ -0,0 +1,90 @@
+package redistrigger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	redis_types "github.com/spinframework/spin-go-sdk/v3/imports/fermyon_spin_redis_types"
+	redis "github.com/spinframework/spin-go-sdk/v3/imports/spin_redis_3_0_0_redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name    string
+		handle  func([]byte) error
+		wantErr string
+		// panics is whether the error also carries a stack trace.
+		panics bool
+	}{{
+		name:   "ok",
+		handle: func([]byte) error { return nil },
+	}, {
+		name:    "error",
+		handle:  func([]byte) error { return errors.New("boom") },
+		wantErr: "boom",
+	}, {
+		name:    "panic",
+		handle:  func([]byte) error { panic("kaboom") },
+		wantErr: "redis: handler panicked: kaboom",
+		panics:  true,
+	}, {
+		name:    "runtime panic",
+		handle:  func([]byte) error { var m map[string]int; m["x"]++; return nil },
+		wantErr: "redis: handler panicked: assignment to entry in nil map",
+		panics:  true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := []byte("hello \x00 world")
+
+			var got, gotLegacy []byte
+			current := Handler(func(m []byte) error {
+				got = m
+				return tt.handle(m)
+			})
+			var log strings.Builder
+			legacy := LegacyHandler(func(m []byte) error {
+				gotLegacy = m
+				return tt.handle(m)
+			}, &log)
+
+			result := current(message)
+			legacyResult := legacy(message)
+
+			assert.Equal(t, message, got)
+			assert.Equal(t, message, gotLegacy)
+			assert.Equal(t, result.IsErr(), legacyResult.IsErr())
+
+			if tt.wantErr == "" {
+				assert.True(t, result.IsOk())
+				assert.Empty(t, log.String())
+				return
+			}
+
+			checkMessage := func(message string) {
+				t.Helper()
+				if !tt.panics {
+					assert.Equal(t, tt.wantErr, message)
+					return
+				}
+				first, stack, _ := strings.Cut(message, "\n")
+				assert.Equal(t, tt.wantErr, first)
+				assert.Contains(t, stack, "runtime/debug.Stack")
+			}
+
+			require.True(t, result.IsErr())
+			assert.Equal(t, redis.ErrorOther, result.Err().Tag())
+			checkMessage(result.Err().Other())
+
+			require.True(t, legacyResult.IsErr())
+			assert.Equal(t, redis_types.ErrorError, legacyResult.Err())
+			logged, ok := strings.CutSuffix(log.String(), "\n")
+			assert.True(t, ok)
+			checkMessage(logged)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxTokens is the number of tokens the host generates when no
+// InferencingParams are given, which Chat reserves in the context window.
+const DefaultMaxTokens = 100
+
+// ErrContextWindowExceeded is returned by Chat when the prompt does not fit in
+// the model's context window even after truncating the conversation history.
+var ErrContextWindowExceeded = errors.New("llm: prompt exceeds the model's context window")
+
+// Role is the author of a chat message.
+type Role string
+
+const (
+	// RoleSystem is the role of instructions that steer the model. A system
+	// message may only be the first message of a conversation.
+	RoleSystem Role = "system"
+	// RoleUser is the role of messages from the user.
+	RoleUser Role = "user"
+	// RoleAssistant is the role of messages from the model.
+	RoleAssistant Role = "assistant"
+)
+
+// Message is a message in a chat conversation.
+type Message struct {
+	Role    Role   `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatTemplate formats chat conversations as prompts for a model.
+type ChatTemplate struct {
+	// Format formats a conversation as a prompt. The conversation's last
+	// message is from the user.
+	Format func(messages []Message) (string, error)
+
+	// ContextWindow is the number of tokens the model accepts, counting both
+	// the prompt and the generated tokens.
+	ContextWindow int
+
+	// CountTokens counts the tokens of a prompt. It defaults to
+	// EstimateTokens.
+	CountTokens func(prompt string) int
+}
+
+var (
+	chatTemplatesMu sync.RWMutex
+	chatTemplates   = map[InferencingModel]ChatTemplate{
+		Llama2Chat:        {Format: FormatLlama2, ContextWindow: 4096},
+		CodellamaInstruct: {Format: FormatLlama2, ContextWindow: 16384},
+	}
+)
+
+// RegisterChatTemplate sets the template that Chat uses for model, replacing
+// any template already registered for it. Templates for Llama2Chat and
+// CodellamaInstruct are registered by default.
+func RegisterChatTemplate(model InferencingModel, template ChatTemplate) {
+	chatTemplatesMu.Lock()
+	defer chatTemplatesMu.Unlock()
+	chatTemplates[model] = template
+}
+
+// LookupChatTemplate returns the template registered for model.
+func LookupChatTemplate(model InferencingModel) (ChatTemplate, bool) {
+	chatTemplatesMu.RLock()
+	defer chatTemplatesMu.RUnlock()
+	template, ok := chatTemplates[model]
+	return template, ok
+}
+
+// ChatResult is the result of a chat request.
+type ChatResult struct {
+	// Message is the model's reply.
+	Message Message
+
+	// Usage is usage information about the request.
+	Usage InferencingUsage
+
+	// Truncated is the number of messages at the start of the conversation,
+	// after any system message, that were left out of the prompt to fit it
+	// in the model's context window.
+	Truncated int
+}
+
+// Chat performs inferencing with a prompt made from a conversation by the
+// template registered for model, returning the model's reply:
+//
+//	result, err := llm.Chat(llm.Llama2Chat, []llm.Message{
+//		{Role: llm.RoleSystem, Content: "You are a helpful assistant."},
+//		{Role: llm.RoleUser, Content: "Tell me a joke"},
+//	}, nil)
+//
+// After an optional system message, the conversation must alternate between
+// user and assistant messages, ending with a user message. If the prompt
+// would not leave room in the model's context window for params.MaxTokens
+// generated tokens, or DefaultMaxTokens if params is nil, the oldest messages
+// after the system message are left out until it does.
+func Chat(model InferencingModel, messages []Message, params *InferencingParams) (ChatResult, error) {
+	return chat(model, messages, params, func(prompt string) (InferencingResult, error) {
+		return Infer(string(model), prompt, params)
+	})
+}
+
+func chat(model InferencingModel, messages []Message, params *InferencingParams, infer func(prompt string) (InferencingResult, error)) (ChatResult, error) {
+	template, ok := LookupChatTemplate(model)
+	if !ok {
+		return ChatResult{}, fmt.Errorf("llm: no chat template for model %q", model)
+	}
+
+	maxTokens := DefaultMaxTokens
+	if params != nil {
+		maxTokens = int(params.MaxTokens)
+	}
+
+	prompt, truncated, err := template.fit(messages, template.ContextWindow-maxTokens)
+	if err != nil {
+		return ChatResult{}, err
+	}
+
+	result, err := infer(prompt)
+	if err != nil {
+		return ChatResult{}, err
+	}
+
+	return ChatResult{
+		Message:   Message{Role: RoleAssistant, Content: strings.TrimSpace(result.Text)},
+		Usage:     result.Usage,
+		Truncated: truncated,
+	}, nil
+}
+
+// fit formats messages as a prompt of at most budget tokens, leaving out the
+// oldest messages after any system message as needed. It returns the prompt
+// and the number of messages left out.
+func (t ChatTemplate) fit(messages []Message, budget int) (string, int, error) {
+	count := t.CountTokens
+	if count == nil {
+		count = EstimateTokens
+	}
+
+	var system []Message
+	if len(messages) > 0 && messages[0].Role == RoleSystem {
+		system, messages = messages[:1], messages[1:]
+	}
+
+	truncated := 0
+	for {
+		prompt, err := t.Format(append(system[:len(system):len(system)], messages...))
+		if err != nil {
+			return "", 0, err
+		}
+		if count(prompt) <= budget {
+			return prompt, truncated, nil
+		}
+		if len(messages) <= 1 {
+			return "", 0, ErrContextWindowExceeded
+		}
+
+		// Drop the oldest exchange, so that the history still starts with
+		// a user message.
+		messages = messages[1:]
+		truncated++
+		for len(messages) > 1 && messages[0].Role != RoleUser {
+			messages = messages[1:]
+			truncated++
+		}
+	}
+}
+
+// EstimateTokens estimates the number of tokens in text. Tokenizers typically
+// produce about one token per four characters of English text; EstimateTokens
+// assumes one per three bytes, so that it rarely underestimates. Register a
+// ChatTemplate with an exact CountTokens for models whose tokenizer is known.
+func EstimateTokens(text string) int {
+	return (len(text) + 2) / 3
+}
+
+// FormatLlama2 formats a conversation with the prompt format of Llama 2 Chat
+// and Code Llama Instruct:
+//
+//	<s>[INST] <<SYS>>
+//	{system}
+//	<</SYS>>
+//
+//	{user} [/INST] {assistant} </s><s>[INST] {user} [/INST]
+func FormatLlama2(messages []Message) (string, error) {
+	var system string
+	offset := 0
+	if len(messages) > 0 && messages[0].Role == RoleSystem {
+		system, messages, offset = strings.TrimSpace(messages[0].Content), messages[1:], 1
+	}
+	if len(messages)%2 == 0 {
+		return "", errors.New("llm: conversation must end with a user message")
+	}
+
+	var b strings.Builder
+	for i, m := range messages {
+		want := RoleUser
+		if i%2 == 1 {
+			want = RoleAssistant
+		}
+		if m.Role != want {
+			return "", fmt.Errorf("llm: message %d has role %q; want %q", offset+i, m.Role, want)
+		}
+
+		content := strings.TrimSpace(m.Content)
+		if m.Role == RoleAssistant {
+			fmt.Fprintf(&b, " %s </s>", content)
+			continue
+		}
+
+		b.WriteString("<s>[INST] ")
+		if i == 0 && system != "" {
+			fmt.Fprintf(&b, "<<SYS>>\n%s\n<</SYS>>\n\n", system)
+		}
+		fmt.Fprintf(&b, "%s [/INST]", content)
+	}
+	return b.String(), nil
+}
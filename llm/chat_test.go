@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatLlama2(t *testing.T) {
+	prompt, err := FormatLlama2([]Message{
+		{Role: RoleSystem, Content: "Be brief."},
+		{Role: RoleUser, Content: "Hi"},
+		{Role: RoleAssistant, Content: "Hello! "},
+		{Role: RoleUser, Content: " Tell me a joke"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "<s>[INST] <<SYS>>\nBe brief.\n<</SYS>>\n\nHi [/INST] Hello! </s><s>[INST] Tell me a joke [/INST]", prompt)
+
+	prompt, err = FormatLlama2([]Message{{Role: RoleUser, Content: "Hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, "<s>[INST] Hi [/INST]", prompt)
+
+	_, err = FormatLlama2([]Message{{Role: RoleSystem, Content: "Be brief."}})
+	assert.EqualError(t, err, "llm: conversation must end with a user message")
+
+	_, err = FormatLlama2([]Message{
+		{Role: RoleSystem, Content: "Be brief."},
+		{Role: RoleUser, Content: "Hi"},
+		{Role: RoleUser, Content: "Hello?"},
+		{Role: RoleUser, Content: "Anyone?"},
+	})
+	assert.EqualError(t, err, `llm: message 2 has role "user"; want "assistant"`)
+}
+
+func TestChat(t *testing.T) {
+	// Count one token per word, for readable budgets.
+	RegisterChatTemplate("test-model", ChatTemplate{
+		Format: func(messages []Message) (string, error) {
+			var words []string
+			for _, m := range messages {
+				words = append(words, m.Content)
+			}
+			return strings.Join(words, " "), nil
+		},
+		ContextWindow: 10,
+		CountTokens:   func(prompt string) int { return len(strings.Fields(prompt)) },
+	})
+
+	messages := []Message{
+		{Role: RoleSystem, Content: "s1 s2"},
+		{Role: RoleUser, Content: "u1 u1"},
+		{Role: RoleAssistant, Content: "a1 a1"},
+		{Role: RoleUser, Content: "u2"},
+		{Role: RoleAssistant, Content: "a2"},
+		{Role: RoleUser, Content: "u3 u3"},
+	}
+
+	tests := []struct {
+		name          string
+		params        *InferencingParams
+		wantPrompt    string
+		wantTruncated int
+		wantErr       error
+	}{{
+		name:       "fits",
+		params:     &InferencingParams{MaxTokens: 0},
+		wantPrompt: "s1 s2 u1 u1 a1 a1 u2 a2 u3 u3",
+	}, {
+		name:          "drops oldest exchange",
+		params:        &InferencingParams{MaxTokens: 4},
+		wantPrompt:    "s1 s2 u2 a2 u3 u3",
+		wantTruncated: 2,
+	}, {
+		name:          "keeps system and last message",
+		params:        &InferencingParams{MaxTokens: 6},
+		wantPrompt:    "s1 s2 u3 u3",
+		wantTruncated: 4,
+	}, {
+		name:    "too long",
+		params:  &InferencingParams{MaxTokens: 7},
+		wantErr: ErrContextWindowExceeded,
+	}, {
+		name:    "default max tokens",
+		wantErr: ErrContextWindowExceeded,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompt string
+			result, err := chat("test-model", messages, tt.params, func(p string) (InferencingResult, error) {
+				prompt = p
+				return InferencingResult{
+					Text:  " Knock knock.\n",
+					Usage: InferencingUsage{PromptTokenCount: 7, GeneratedTokenCount: 3},
+				}, nil
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPrompt, prompt)
+			assert.Equal(t, ChatResult{
+				Message:   Message{Role: RoleAssistant, Content: "Knock knock."},
+				Usage:     InferencingUsage{PromptTokenCount: 7, GeneratedTokenCount: 3},
+				Truncated: tt.wantTruncated,
+			}, result)
+		})
+	}
+}
+
+func TestChatErrors(t *testing.T) {
+	infer := func(string) (InferencingResult, error) { return InferencingResult{}, errors.New("runtime error") }
+
+	_, err := chat("unknown-model", []Message{{Role: RoleUser, Content: "Hi"}}, nil, infer)
+	assert.EqualError(t, err, `llm: no chat template for model "unknown-model"`)
+
+	_, err = chat(Llama2Chat, []Message{{Role: RoleUser, Content: "Hi"}}, nil, infer)
+	assert.EqualError(t, err, "runtime error")
+
+	_, err = chat(Llama2Chat, []Message{{Role: RoleAssistant, Content: "Hi"}}, nil, infer)
+	assert.EqualError(t, err, `llm: message 0 has role "assistant"; want "user"`)
+}
+
+func TestDefaultChatTemplates(t *testing.T) {
+	for _, model := range []InferencingModel{Llama2Chat, CodellamaInstruct} {
+		template, ok := LookupChatTemplate(model)
+		require.True(t, ok, model)
+		assert.Positive(t, template.ContextWindow)
+	}
+	assert.Equal(t, 5, EstimateTokens("Tell me a joke"))
+}
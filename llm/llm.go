@@ -41,8 +41,8 @@ type InferencingParams struct {
 
 // InferencingResult represents the result of an inferencing request.
 type InferencingResult struct {
-	// The text generated by the model
-	// TODO: this should be a stream
+	// The text generated by the model. See InferStream to receive the text
+	// as a sequence of chunks.
 	Text string `json:"text"`
 
 	// Usage information about the inferencing request
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"iter"
+)
+
+// InferStream performs inferencing like Infer, returning the generated text as
+// a sequence of chunks. The sequence ends after the first error, which is
+// ctx.Err() if ctx is done before the text has been generated.
+//
+// The Spin LLM interface does not stream yet, so the sequence currently yields
+// the whole text as a single chunk once inferencing completes, and ctx is only
+// checked before and after the call to the host. Callers written against
+// InferStream will receive tokens as they are generated once the host
+// interface supports it:
+//
+//	for chunk, err := range llm.InferStream(ctx, "llama2-chat", prompt, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Fprint(w, chunk)
+//	}
+func InferStream(ctx context.Context, model string, prompt string, params *InferencingParams) iter.Seq2[string, error] {
+	return inferStream(ctx, func() (InferencingResult, error) {
+		return Infer(model, prompt, params)
+	})
+}
+
+// inferStream adapts a blocking inferencing call to a sequence of chunks.
+func inferStream(ctx context.Context, infer func() (InferencingResult, error)) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield("", err)
+			return
+		}
+
+		result, err := infer()
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			yield("", err)
+			return
+		}
+
+		if result.Text != "" {
+			yield(result.Text, nil)
+		}
+	}
+}
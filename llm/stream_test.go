@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type chunk struct {
+	text string
+	err  error
+}
+
+func collect(t *testing.T, ctx context.Context, infer func() (InferencingResult, error)) []chunk {
+	t.Helper()
+	var chunks []chunk
+	for text, err := range inferStream(ctx, infer) {
+		chunks = append(chunks, chunk{text, err})
+	}
+	return chunks
+}
+
+func TestInferStream(t *testing.T) {
+	boom := errors.New("boom")
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		result InferencingResult
+		err    error
+		want   []chunk
+		called bool
+	}{{
+		name:   "text",
+		ctx:    context.Background(),
+		result: InferencingResult{Text: "Why did the gopher cross the road?"},
+		want:   []chunk{{text: "Why did the gopher cross the road?"}},
+		called: true,
+	}, {
+		name:   "empty",
+		ctx:    context.Background(),
+		called: true,
+	}, {
+		name:   "error",
+		ctx:    context.Background(),
+		err:    boom,
+		want:   []chunk{{err: boom}},
+		called: true,
+	}, {
+		name: "canceled",
+		ctx:  canceled,
+		want: []chunk{{err: context.Canceled}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			got := collect(t, tt.ctx, func() (InferencingResult, error) {
+				called = true
+				return tt.result, tt.err
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.called, called)
+		})
+	}
+}
+
+func TestInferStreamCanceledDuringInference(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	got := collect(t, ctx, func() (InferencingResult, error) {
+		cancel()
+		return InferencingResult{Text: "too late"}, nil
+	})
+	assert.Equal(t, []chunk{{err: context.Canceled}}, got)
+}
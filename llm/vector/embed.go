@@ -0,0 +1,82 @@
+package vector
+
+import (
+	"fmt"
+
+	"github.com/spinframework/spin-go-sdk/v3/llm"
+)
+
+const (
+	// DefaultBatchSize is the number of texts that Embed sends to the host at
+	// once when EmbedOptions.BatchSize is zero.
+	DefaultBatchSize = 32
+	// DefaultBatchTokens is the estimated number of tokens that Embed sends
+	// to the host at once when EmbedOptions.BatchTokens is zero.
+	DefaultBatchTokens = 8192
+)
+
+// EmbedOptions configures Embed.
+type EmbedOptions struct {
+	// BatchSize is the maximum number of texts per request to the host. It
+	// defaults to DefaultBatchSize.
+	BatchSize int
+	// BatchTokens is the maximum number of tokens per request to the host,
+	// as estimated by CountTokens. A text with more tokens is sent on its
+	// own. It defaults to DefaultBatchTokens.
+	BatchTokens int
+	// CountTokens counts the tokens of a text. It defaults to
+	// llm.EstimateTokens.
+	CountTokens func(text string) int
+}
+
+// Embed generates the embeddings of texts with model, splitting them into
+// batches that respect the limits in opts. The embeddings are returned in the
+// order of texts.
+func Embed(model llm.EmbeddingModel, texts []string, opts EmbedOptions) ([][]float32, error) {
+	return embed(func(batch []string) ([][]float32, error) {
+		result, err := llm.GenerateEmbeddings(model, batch)
+		if err != nil {
+			return nil, err
+		}
+		return result.Embeddings, nil
+	}, texts, opts)
+}
+
+func embed(generate func(batch []string) ([][]float32, error), texts []string, opts EmbedOptions) ([][]float32, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	batchTokens := opts.BatchTokens
+	if batchTokens <= 0 {
+		batchTokens = DefaultBatchTokens
+	}
+	count := opts.CountTokens
+	if count == nil {
+		count = llm.EstimateTokens
+	}
+
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); {
+		end, tokens := start, 0
+		for end < len(texts) && end-start < batchSize {
+			n := count(texts[end])
+			if end > start && tokens+n > batchTokens {
+				break
+			}
+			tokens += n
+			end++
+		}
+
+		batch, err := generate(texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("vector: embedding texts %d to %d: %w", start, end-1, err)
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("vector: host returned %d embeddings for %d texts", len(batch), end-start)
+		}
+		embeddings = append(embeddings, batch...)
+		start = end
+	}
+	return embeddings, nil
+}
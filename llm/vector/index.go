@@ -0,0 +1,134 @@
+package vector
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Document is an embedded document in an index.
+type Document struct {
+	// ID identifies the document. Adding a document replaces any document
+	// with the same ID.
+	ID string `json:"id"`
+	// Text is the text that was embedded, returned with search results.
+	Text string `json:"text,omitempty"`
+	// Vector is the embedding of the document.
+	Vector []float32 `json:"-"`
+	// Metadata holds attributes of the document that searches can filter
+	// on, such as its source or language.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Match is a document found by a search.
+type Match struct {
+	Document
+	// Score is the similarity of the document to the query, as scored by
+	// the index's Metric.
+	Score float32
+}
+
+// Filter restricts a search to documents whose metadata has each of its keys
+// with the given value. A nil Filter matches every document.
+type Filter map[string]string
+
+// Match reports whether metadata matches the filter.
+func (f Filter) Match(metadata map[string]string) bool {
+	for key, value := range f {
+		if v, ok := metadata[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Store persists the documents of an index.
+type Store interface {
+	// Put stores docs, replacing any documents with the same IDs.
+	Put(ctx context.Context, docs ...Document) error
+	// Delete removes the documents with the given IDs, ignoring IDs that
+	// are not stored.
+	Delete(ctx context.Context, ids ...string) error
+	// Scan returns every stored document. A non-nil error ends the
+	// sequence.
+	Scan(ctx context.Context) iter.Seq2[Document, error]
+}
+
+// Index searches the documents in a Store by the similarity of their vectors
+// to a query.
+type Index struct {
+	store  Store
+	metric Metric
+}
+
+// NewIndex returns an Index of the documents in store, scored by metric.
+func NewIndex(store Store, metric Metric) *Index {
+	return &Index{store: store, metric: metric}
+}
+
+// Add adds docs to the index, replacing any documents with the same IDs.
+func (ix *Index) Add(ctx context.Context, docs ...Document) error {
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return errors.New("vector: document has no ID")
+		}
+		if len(doc.Vector) == 0 {
+			return fmt.Errorf("vector: document %q has no vector", doc.ID)
+		}
+	}
+	return ix.store.Put(ctx, docs...)
+}
+
+// Delete removes the documents with the given IDs from the index.
+func (ix *Index) Delete(ctx context.Context, ids ...string) error {
+	return ix.store.Delete(ctx, ids...)
+}
+
+// Search returns the k documents matching filter that are most similar to
+// query, most similar first. Documents with equal scores are ordered by ID.
+// It returns an error if a matching document's vector has a different length
+// from query.
+func (ix *Index) Search(ctx context.Context, query []float32, k int, filter Filter) ([]Match, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+
+	// top holds the best matches so far, worst first once it is full.
+	top := make([]Match, 0, k+1)
+	for doc, err := range ix.store.Scan(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Match(doc.Metadata) {
+			continue
+		}
+		if len(doc.Vector) != len(query) {
+			return nil, fmt.Errorf("vector: document %q has %d dimensions; query has %d", doc.ID, len(doc.Vector), len(query))
+		}
+
+		m := Match{Document: doc, Score: ix.metric(query, doc.Vector)}
+		if len(top) == k && compareMatches(m, top[0]) >= 0 {
+			continue
+		}
+		i, _ := slices.BinarySearchFunc(top, m, func(a, b Match) int { return compareMatches(b, a) })
+		top = slices.Insert(top, i, m)
+		if len(top) > k {
+			top = slices.Delete(top, 0, 1)
+		}
+	}
+
+	slices.Reverse(top)
+	return top, nil
+}
+
+// compareMatches returns a negative number if a ranks before b: if it scores
+// higher, or scores the same and has a lower ID.
+func compareMatches(a, b Match) int {
+	if c := cmp.Compare(b.Score, a.Score); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
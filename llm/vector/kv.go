@@ -0,0 +1,116 @@
+package vector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Bucket is a key-value store, such as a *kv.Store.
+type Bucket interface {
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte) error
+	Delete(key string) error
+	GetKeys() iter.Seq2[string, error]
+}
+
+// KVStore is a Store that keeps each document as a JSON value in a key-value
+// store, under its ID prefixed by the store's prefix. Vectors are encoded by
+// Encode.
+type KVStore struct {
+	bucket Bucket
+	prefix string
+}
+
+// NewKVStore returns a Store that keeps documents in bucket under keys
+// starting with prefix, such as "vectors:". Every key with the prefix must
+// hold a document.
+func NewKVStore(bucket Bucket, prefix string) *KVStore {
+	return &KVStore{bucket: bucket, prefix: prefix}
+}
+
+// kvDocument is the stored form of a Document.
+type kvDocument struct {
+	Document
+	Vector []byte `json:"vector"`
+}
+
+// Put implements Store.
+func (s *KVStore) Put(ctx context.Context, docs ...Document) error {
+	for _, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		value, err := json.Marshal(kvDocument{Document: doc, Vector: Encode(doc.Vector)})
+		if err != nil {
+			return err
+		}
+		if err := s.bucket.Set(s.prefix+doc.ID, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *KVStore) Delete(ctx context.Context, ids ...string) error {
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.bucket.Delete(s.prefix + id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan implements Store.
+func (s *KVStore) Scan(ctx context.Context) iter.Seq2[Document, error] {
+	return func(yield func(Document, error) bool) {
+		for key, err := range s.bucket.GetKeys() {
+			if err == nil {
+				err = ctx.Err()
+			}
+			if err != nil {
+				yield(Document{}, err)
+				return
+			}
+			if !strings.HasPrefix(key, s.prefix) {
+				continue
+			}
+
+			doc, err := s.get(key)
+			if err != nil {
+				yield(Document{}, err)
+				return
+			}
+			if doc == nil {
+				// Deleted since the keys were listed.
+				continue
+			}
+			if !yield(*doc, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *KVStore) get(key string) (*Document, error) {
+	value, err := s.bucket.Get(key)
+	if err != nil || len(value) == 0 {
+		return nil, err
+	}
+
+	var stored kvDocument
+	if err := json.Unmarshal(value, &stored); err != nil {
+		return nil, fmt.Errorf("vector: decoding document %q: %w", key, err)
+	}
+	doc := stored.Document
+	if doc.Vector, err = Decode(stored.Vector); err != nil {
+		return nil, fmt.Errorf("vector: document %q: %w", key, err)
+	}
+	return &doc, nil
+}
@@ -0,0 +1,143 @@
+package vector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"regexp"
+	"strings"
+)
+
+// sqliteBatchSize is the number of documents that SQLiteStore.Put inserts
+// per statement.
+const sqliteBatchSize = 100
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLiteStore is a Store that keeps documents in a SQLite table, such as one
+// in a database opened with sqlite.Open. Vectors are stored as blobs encoded
+// by Encode, and metadata as JSON.
+type SQLiteStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLiteStore returns a Store that keeps documents in table in db. Call
+// CreateTable to create the table if it does not exist.
+func NewSQLiteStore(db *sql.DB, table string) (*SQLiteStore, error) {
+	if !identifier.MatchString(table) {
+		return nil, fmt.Errorf("vector: invalid table name %q", table)
+	}
+	return &SQLiteStore{db: db, table: table}, nil
+}
+
+// CreateTable creates the store's table if it does not exist.
+func (s *SQLiteStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT PRIMARY KEY,
+	text TEXT NOT NULL,
+	vector BLOB NOT NULL,
+	metadata TEXT
+)`, s.table))
+	return err
+}
+
+// Put implements Store.
+func (s *SQLiteStore) Put(ctx context.Context, docs ...Document) error {
+	for len(docs) > 0 {
+		n := min(len(docs), sqliteBatchSize)
+		batch := docs[:n]
+		docs = docs[n:]
+
+		var args []any
+		for _, doc := range batch {
+			metadata, err := encodeMetadata(doc.Metadata)
+			if err != nil {
+				return err
+			}
+			args = append(args, doc.ID, doc.Text, Encode(doc.Vector), metadata)
+		}
+
+		values := strings.Repeat("(?, ?, ?, ?), ", len(batch))
+		query := fmt.Sprintf(`INSERT INTO %s (id, text, vector, metadata) VALUES %s
+ON CONFLICT (id) DO UPDATE SET text = excluded.text, vector = excluded.vector, metadata = excluded.metadata`,
+			s.table, strings.TrimSuffix(values, ", "))
+		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *SQLiteStore) Delete(ctx context.Context, ids ...string) error {
+	for len(ids) > 0 {
+		n := min(len(ids), sqliteBatchSize)
+		batch := ids[:n]
+		ids = ids[n:]
+
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", ")
+		query := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", s.table, placeholders)
+		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan implements Store.
+func (s *SQLiteStore) Scan(ctx context.Context) iter.Seq2[Document, error] {
+	return func(yield func(Document, error) bool) {
+		rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT id, text, vector, metadata FROM %s", s.table))
+		if err != nil {
+			yield(Document{}, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var doc Document
+			var blob []byte
+			var metadata sql.NullString
+			if err := rows.Scan(&doc.ID, &doc.Text, &blob, &metadata); err != nil {
+				yield(Document{}, err)
+				return
+			}
+			if doc.Vector, err = Decode(blob); err != nil {
+				yield(Document{}, fmt.Errorf("vector: document %q: %w", doc.ID, err))
+				return
+			}
+			if metadata.Valid {
+				if err := json.Unmarshal([]byte(metadata.String), &doc.Metadata); err != nil {
+					yield(Document{}, fmt.Errorf("vector: decoding metadata of document %q: %w", doc.ID, err))
+					return
+				}
+			}
+			if !yield(doc, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(Document{}, err)
+		}
+	}
+}
+
+// encodeMetadata encodes metadata as JSON, or as NULL if it is empty.
+func encodeMetadata(metadata map[string]string) (any, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
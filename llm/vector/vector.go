@@ -0,0 +1,118 @@
+// Package vector stores embeddings, such as those returned by
+// llm.GenerateEmbeddings, and searches them by similarity, for
+// retrieval-augmented generation within a Spin component:
+//
+//	store, err := vector.NewSQLiteStore(sqlite.Open("default"), "documents")
+//	// if err != nil { ... }
+//	err = store.CreateTable(ctx)
+//	index := vector.NewIndex(store, vector.Cosine)
+//
+//	embeddings, err := vector.Embed("all-minilm-l6-v2", texts, vector.EmbedOptions{})
+//	// if err != nil { ... }
+//	for i, text := range texts {
+//		docs = append(docs, vector.Document{ID: ids[i], Text: text, Vector: embeddings[i]})
+//	}
+//	err = index.Add(ctx, docs...)
+//
+//	query, err := vector.Embed("all-minilm-l6-v2", []string{question}, vector.EmbedOptions{})
+//	matches, err := index.Search(ctx, query[0], 3, vector.Filter{"lang": "en"})
+//
+// A KVStore keeps documents in a key-value store instead.
+//
+// Searches are exact: each search scans every document in the store, which
+// suits the collections of up to some tens of thousands of documents that a
+// component typically holds.
+package vector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Metric scores the similarity of two vectors of the same length. Higher
+// scores are more similar.
+type Metric func(a, b []float32) float32
+
+// Cosine is the Metric that scores vectors by the cosine of the angle between
+// them, from -1 to 1. It scores a zero vector as 0.
+func Cosine(a, b []float32) float32 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+// Dot is the Metric that scores vectors by their dot product. For normalized
+// vectors, it is equivalent to Cosine, and faster.
+func Dot(a, b []float32) float32 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return float32(dot)
+}
+
+// L2 is the Metric that scores vectors by the negation of the Euclidean
+// distance between them, so that nearer vectors score higher.
+func L2(a, b []float32) float32 {
+	return -Distance(a, b)
+}
+
+// Distance returns the Euclidean distance between a and b.
+func Distance(a, b []float32) float32 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return float32(math.Sqrt(sum))
+}
+
+// Normalize returns v scaled to unit length, or v itself if it is a zero
+// vector.
+func Normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+
+	norm := math.Sqrt(sum)
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// Encode encodes v as a blob of little-endian IEEE 754 floats, four bytes per
+// element.
+func Encode(v []float32) []byte {
+	b := make([]byte, 0, 4*len(v))
+	for _, x := range v {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(x))
+	}
+	return b
+}
+
+// Decode decodes a blob encoded by Encode.
+func Decode(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("vector: encoded vector has %d bytes, not a multiple of 4", len(b))
+	}
+
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v, nil
+}
@@ -0,0 +1,287 @@
+package vector
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spinframework/spin-go-sdk/v3/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	a := []float32{1, 0}
+	b := []float32{0, 1}
+	c := []float32{3, 4}
+
+	assert.InDelta(t, 0, Cosine(a, b), 1e-6)
+	assert.InDelta(t, 1, Cosine(c, c), 1e-6)
+	assert.InDelta(t, 0.6, Cosine(a, c), 1e-6)
+	assert.Equal(t, float32(0), Cosine(a, []float32{0, 0}))
+
+	assert.Equal(t, float32(3), Dot(a, c))
+	assert.Equal(t, float32(5), Distance([]float32{0, 0}, c))
+	assert.Equal(t, float32(-5), L2([]float32{0, 0}, c))
+
+	assert.Equal(t, []float32{0.6, 0.8}, Normalize(c))
+	assert.Equal(t, []float32{0, 0}, Normalize([]float32{0, 0}))
+}
+
+func TestEncode(t *testing.T) {
+	v := []float32{1.5, -2, 0, float32(math.Inf(1))}
+	b := Encode(v)
+	assert.Len(t, b, 16)
+	assert.Equal(t, []byte{0, 0, 0xc0, 0x3f}, b[:4])
+
+	got, err := Decode(b)
+	require.NoError(t, err)
+	assert.Equal(t, v, got)
+
+	_, err = Decode([]byte{1, 2, 3})
+	assert.EqualError(t, err, "vector: encoded vector has 3 bytes, not a multiple of 4")
+}
+
+// fakeBucket is an in-memory Bucket.
+type fakeBucket map[string][]byte
+
+func (b fakeBucket) Get(key string) ([]byte, error) { return b[key], nil }
+
+func (b fakeBucket) Set(key string, value []byte) error {
+	b[key] = value
+	return nil
+}
+
+func (b fakeBucket) Delete(key string) error {
+	delete(b, key)
+	return nil
+}
+
+func (b fakeBucket) GetKeys() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for _, key := range slices.Sorted(maps.Keys(b)) {
+			if !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+	bucket := fakeBucket{"other": []byte("not a document")}
+	index := NewIndex(NewKVStore(bucket, "vectors:"), Cosine)
+
+	require.NoError(t, index.Add(ctx,
+		Document{ID: "north", Text: "Go north", Vector: []float32{0, 1}, Metadata: map[string]string{"lang": "en"}},
+		Document{ID: "east", Text: "Go east", Vector: []float32{1, 0}, Metadata: map[string]string{"lang": "en"}},
+		Document{ID: "nord", Text: "Va au nord", Vector: []float32{0, 2}, Metadata: map[string]string{"lang": "fr"}},
+		Document{ID: "northeast", Text: "Go northeast", Vector: []float32{1, 1}},
+	))
+	assert.Contains(t, bucket, "vectors:north")
+
+	matches, err := index.Search(ctx, []float32{0.1, 1}, 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nord", "north", "northeast"}, ids(matches))
+	assert.InDelta(t, Cosine([]float32{0.1, 1}, []float32{1, 1}), matches[2].Score, 1e-6)
+	assert.Equal(t, "Va au nord", matches[0].Text)
+	assert.Equal(t, []float32{0, 2}, matches[0].Vector)
+	assert.Equal(t, map[string]string{"lang": "fr"}, matches[0].Metadata)
+
+	matches, err = index.Search(ctx, []float32{0.1, 1}, 10, Filter{"lang": "en"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"north", "east"}, ids(matches))
+
+	matches, err = index.Search(ctx, []float32{0.1, 1}, 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	require.NoError(t, index.Delete(ctx, "nord", "missing"))
+	require.NoError(t, index.Add(ctx, Document{ID: "east", Text: "Go east", Vector: []float32{0, 1}}))
+	matches, err = index.Search(ctx, []float32{0, 1}, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"east", "north"}, ids(matches))
+
+	_, err = index.Search(ctx, []float32{0, 1, 0}, 2, nil)
+	assert.ErrorContains(t, err, "has 2 dimensions; query has 3")
+
+	assert.EqualError(t, index.Add(ctx, Document{Vector: []float32{1}}), "vector: document has no ID")
+	assert.EqualError(t, index.Add(ctx, Document{ID: "x"}), `vector: document "x" has no vector`)
+}
+
+func TestIndexL2(t *testing.T) {
+	ctx := context.Background()
+	index := NewIndex(NewKVStore(fakeBucket{}, ""), L2)
+	for i := range 10 {
+		require.NoError(t, index.Add(ctx, Document{ID: fmt.Sprint(i), Vector: []float32{float32(i)}}))
+	}
+
+	matches, err := index.Search(ctx, []float32{6.2}, 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6", "7", "5"}, ids(matches))
+}
+
+func ids(matches []Match) []string {
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestEmbed(t *testing.T) {
+	texts := []string{"aaaa", "bb", "cccccc", "d", "eeeeeeeeeeee", "f"}
+
+	var batches [][]string
+	generate := func(batch []string) ([][]float32, error) {
+		batches = append(batches, batch)
+		var embeddings [][]float32
+		for _, text := range batch {
+			embeddings = append(embeddings, []float32{float32(len(text))})
+		}
+		return embeddings, nil
+	}
+
+	embeddings, err := embed(generate, texts, EmbedOptions{
+		BatchSize:   3,
+		BatchTokens: 8,
+		CountTokens: func(text string) int { return len(text) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{4}, {2}, {6}, {1}, {12}, {1}}, embeddings)
+	assert.Equal(t, [][]string{{"aaaa", "bb"}, {"cccccc", "d"}, {"eeeeeeeeeeee"}, {"f"}}, batches)
+
+	batches = nil
+	_, err = embed(generate, texts, EmbedOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{texts}, batches)
+
+	_, err = embed(func([]string) ([][]float32, error) { return nil, errors.New("invalid input") }, texts, EmbedOptions{BatchSize: 4})
+	assert.EqualError(t, err, "vector: embedding texts 0 to 3: invalid input")
+
+	_, err = embed(func([]string) ([][]float32, error) { return nil, nil }, texts, EmbedOptions{})
+	assert.EqualError(t, err, "vector: host returned 0 embeddings for 6 texts")
+
+	embeddings, err = embed(generate, nil, EmbedOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, embeddings)
+}
+
+func TestSQLiteStore(t *testing.T) {
+	type statement struct {
+		query string
+		args  []driver.Value
+	}
+	var statements []statement
+	conn := &dbtest.Conn{
+		Exec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+			values := make([]driver.Value, len(args))
+			for i, arg := range args {
+				require.Equal(t, i+1, arg.Ordinal)
+				values[i] = arg.Value
+			}
+			statements = append(statements, statement{query, values})
+			return driver.RowsAffected(0), nil
+		},
+	}
+	store, err := NewSQLiteStore(dbtest.Open(t, conn), "docs")
+	require.NoError(t, err)
+
+	_, err = NewSQLiteStore(dbtest.Open(t, conn), "docs; DROP TABLE x")
+	assert.EqualError(t, err, `vector: invalid table name "docs; DROP TABLE x"`)
+
+	t.Run("Put", func(t *testing.T) {
+		statements = nil
+		docs := make([]Document, sqliteBatchSize+1)
+		for i := range docs {
+			docs[i] = Document{ID: fmt.Sprint("doc", i), Text: fmt.Sprint("text", i), Vector: []float32{float32(i)}}
+		}
+		docs[sqliteBatchSize].Metadata = map[string]string{"lang": "en"}
+
+		require.NoError(t, store.Put(context.Background(), docs...))
+		require.Len(t, statements, 2)
+
+		const upsert = "INSERT INTO docs (id, text, vector, metadata) VALUES %s\n" +
+			"ON CONFLICT (id) DO UPDATE SET text = excluded.text, vector = excluded.vector, metadata = excluded.metadata"
+		values := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?), ", sqliteBatchSize), ", ")
+		assert.Equal(t, fmt.Sprintf(upsert, values), statements[0].query)
+		require.Len(t, statements[0].args, 4*sqliteBatchSize)
+		for i := range sqliteBatchSize {
+			doc := docs[i]
+			assert.Equal(t, []driver.Value{doc.ID, doc.Text, Encode(doc.Vector), nil}, statements[0].args[4*i:4*i+4])
+		}
+
+		assert.Equal(t, fmt.Sprintf(upsert, "(?, ?, ?, ?)"), statements[1].query)
+		assert.Equal(t, []driver.Value{"doc100", "text100", Encode([]float32{100}), `{"lang":"en"}`}, statements[1].args)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		statements = nil
+		ids := make([]string, sqliteBatchSize+1)
+		want := make([]driver.Value, sqliteBatchSize)
+		for i := range ids {
+			ids[i] = fmt.Sprint("doc", i)
+			if i < sqliteBatchSize {
+				want[i] = ids[i]
+			}
+		}
+
+		require.NoError(t, store.Delete(context.Background(), ids...))
+		require.Len(t, statements, 2)
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", sqliteBatchSize), ", ")
+		assert.Equal(t, "DELETE FROM docs WHERE id IN ("+placeholders+")", statements[0].query)
+		assert.Equal(t, want, statements[0].args)
+		assert.Equal(t, "DELETE FROM docs WHERE id IN (?)", statements[1].query)
+		assert.Equal(t, []driver.Value{"doc100"}, statements[1].args)
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		conn.Query = func(query string, args []driver.NamedValue) (driver.Rows, error) {
+			assert.Equal(t, "SELECT id, text, vector, metadata FROM docs", query)
+			assert.Empty(t, args)
+			rows := &dbtest.Rows{
+				Columns: []string{"id", "text", "vector", "metadata"},
+				Values: [][]driver.Value{
+					{"a", "first", Encode([]float32{1, 2}), nil},
+					{"b", "second", Encode([]float32{3}), `{"lang":"en"}`},
+				},
+			}
+			return rows.Iter(), nil
+		}
+
+		var docs []Document
+		for doc, err := range store.Scan(context.Background()) {
+			require.NoError(t, err)
+			docs = append(docs, doc)
+		}
+		assert.Equal(t, []Document{
+			{ID: "a", Text: "first", Vector: []float32{1, 2}},
+			{ID: "b", Text: "second", Vector: []float32{3}, Metadata: map[string]string{"lang": "en"}},
+		}, docs)
+	})
+
+	t.Run("InvalidMetadata", func(t *testing.T) {
+		conn.Query = func(string, []driver.NamedValue) (driver.Rows, error) {
+			rows := &dbtest.Rows{
+				Columns: []string{"id", "text", "vector", "metadata"},
+				Values:  [][]driver.Value{{"a", "first", Encode([]float32{1}), "{"}},
+			}
+			return rows.Iter(), nil
+		}
+
+		var errs []error
+		for _, err := range store.Scan(context.Background()) {
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], `vector: decoding metadata of document "a"`)
+	})
+}
@@ -0,0 +1,77 @@
+package migrate
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Handler returns an HTTP handler for administering migrations, which callers
+// mount on a route of their choosing. Requests must carry the header
+// "Authorization: Bearer <token>"; if token is empty, every request is
+// refused.
+//
+// GET responds with the status of all the migrations. POST applies the
+// pending migrations and responds with those applied, or, with the query
+// parameter dry_run=true, responds with the pending migrations without
+// applying them. Responses are JSON objects with a "migrations" array and, on
+// failure, an "error" string.
+func (m *Migrator) Handler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, response{Error: "unauthorized"})
+			return
+		}
+
+		var migrations []Migration
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			migrations, err = m.Status(r.Context())
+		case http.MethodPost:
+			dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+			if dryRun {
+				migrations, err = m.Pending(r.Context())
+			} else {
+				migrations, err = m.Up(r.Context())
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, response{Error: "method not allowed"})
+			return
+		}
+
+		res := response{Migrations: migrations}
+		if res.Migrations == nil {
+			res.Migrations = []Migration{}
+		}
+		status := http.StatusOK
+		if err != nil {
+			res.Error = err.Error()
+			status = http.StatusInternalServerError
+		}
+		writeJSON(w, status, res)
+	})
+}
+
+type response struct {
+	Migrations []Migration `json:"migrations"`
+	Error      string      `json:"error,omitempty"`
+}
+
+func authorized(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/spinframework/spin-go-sdk/v3/imports/wasi_keyvalue_0_2_0_draft2_atomics"
+	store "github.com/spinframework/spin-go-sdk/v3/imports/wasi_keyvalue_0_2_0_draft2_store"
+)
+
+type kvBucket struct {
+	bucket *store.Bucket
+}
+
+func openLockBucket(label string) (lockBucket, error) {
+	result := store.Open(label)
+	if result.IsErr() {
+		return nil, storeError(result.Err())
+	}
+	return kvBucket{bucket: result.Ok()}, nil
+}
+
+func (b kvBucket) tryLock(key string, value []byte) (bool, error) {
+	casResult := wasi_keyvalue_0_2_0_draft2_atomics.CasNew(b.bucket, key)
+	if casResult.IsErr() {
+		return false, storeError(casResult.Err())
+	}
+	cas := casResult.Ok()
+
+	current := cas.Current()
+	if current.IsErr() {
+		cas.Drop()
+		return false, storeError(current.Err())
+	}
+	if current := current.Ok(); current.IsSome() && !lockExpired(current.Some()) {
+		cas.Drop()
+		return false, nil
+	}
+
+	// Swap consumes cas.
+	swapped := wasi_keyvalue_0_2_0_draft2_atomics.Swap(cas, value)
+	if swapped.IsErr() {
+		casErr := swapped.Err()
+		if casErr.Tag() == wasi_keyvalue_0_2_0_draft2_atomics.CasErrorCasFailed {
+			casErr.CasFailed().Drop()
+			return false, nil
+		}
+		return false, storeError(casErr.StoreError())
+	}
+	return true, nil
+}
+
+func (b kvBucket) unlock(key string, value []byte) error {
+	result := b.bucket.Get(key)
+	if result.IsErr() {
+		return storeError(result.Err())
+	}
+	if current := result.Ok(); current.IsNone() || !bytes.Equal(current.Some(), value) {
+		// The lock expired and was taken by another instance.
+		return nil
+	}
+	if result := b.bucket.Delete(key); result.IsErr() {
+		return storeError(result.Err())
+	}
+	return nil
+}
+
+func (b kvBucket) close() {
+	b.bucket.Drop()
+}
+
+func storeError(err store.Error) error {
+	switch err.Tag() {
+	case store.ErrorNoSuchStore:
+		return errors.New("no such key-value store")
+	case store.ErrorAccessDenied:
+		return errors.New("access denied to key-value store")
+	case store.ErrorOther:
+		return fmt.Errorf("key-value store: %s", err.Other())
+	default:
+		return errors.New("key-value store: unknown error")
+	}
+}
@@ -0,0 +1,180 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// lockRetryInterval is how long to wait before retrying a held lock.
+const lockRetryInterval = 100 * time.Millisecond
+
+// Locker serializes the application of migrations across instances of a
+// component.
+type Locker interface {
+	// Lock blocks until the lock is acquired or ctx is done, and returns a
+	// function that releases the lock. conn is the connection on which the
+	// migrations will be applied.
+	Lock(ctx context.Context, conn *sql.Conn) (unlock func() error, err error)
+}
+
+// AdvisoryLock is a PostgreSQL session advisory lock with the given key.
+//
+// A session lock outlives the transaction that took it, and closing a
+// *sql.Conn returns its session to the pool rather than ending it, so the
+// lock is released explicitly by unlock. If the outcome of taking or
+// releasing the lock is unknown, the connection is discarded instead of being
+// returned to the pool, which ends the session and releases the lock with it.
+type AdvisoryLock int64
+
+// advisoryLockKey returns the default advisory lock key for a migrations
+// table.
+func advisoryLockKey(table string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("migrate:" + table))
+	return int64(h.Sum64())
+}
+
+// Lock implements Locker.
+func (l AdvisoryLock) Lock(ctx context.Context, conn *sql.Conn) (func() error, error) {
+	for {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", int64(l)).Scan(&acquired); err != nil {
+			// The lock may have been taken even though the result was lost.
+			discard(conn)
+			return nil, err
+		}
+		if acquired {
+			break
+		}
+		if err := sleep(ctx, lockRetryInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	return func() error {
+		var released bool
+		err := conn.QueryRowContext(context.Background(), "SELECT pg_advisory_unlock($1)", int64(l)).Scan(&released)
+		if err == nil && !released {
+			err = fmt.Errorf("advisory lock %d was not held", int64(l))
+		}
+		if err != nil {
+			discard(conn)
+		}
+		return err
+	}, nil
+}
+
+// discard closes the driver connection underlying conn instead of returning
+// it to the pool, ending its database session.
+func discard(conn *sql.Conn) {
+	conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+}
+
+// KVLock is a lock held in a key-value store, for databases such as SQLite
+// that have no locks of their own. It requires the component to have access
+// to the store.
+//
+// The lock expires after TTL so that a crashed instance cannot hold it
+// forever; migrations taking longer than TTL may therefore run concurrently.
+// KVLock uses the wasi:keyvalue interfaces.
+type KVLock struct {
+	// Store is the label of the key-value store.
+	Store string
+	// Key is the key under which the lock is held.
+	Key string
+	// TTL is how long the lock is held before it expires. It defaults to
+	// one minute.
+	TTL time.Duration
+}
+
+// Lock implements Locker.
+func (l *KVLock) Lock(ctx context.Context, _ *sql.Conn) (func() error, error) {
+	bucket, err := openLockBucket(l.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := l.lock(ctx, bucket)
+	if err != nil {
+		bucket.close()
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// lock takes the lock in bucket. The returned unlock function closes bucket.
+func (l *KVLock) lock(ctx context.Context, bucket lockBucket) (func() error, error) {
+	token := make([]byte, 16)
+	rand.Read(token)
+
+	for {
+		ttl := l.TTL
+		if ttl <= 0 {
+			ttl = time.Minute
+		}
+		value := lockValue(hex.EncodeToString(token), time.Now().Add(ttl))
+
+		acquired, err := bucket.tryLock(l.Key, value)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return func() error {
+				defer bucket.close()
+				return bucket.unlock(l.Key, value)
+			}, nil
+		}
+		if err := sleep(ctx, lockRetryInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// lockBucket is the key-value store holding a KVLock.
+type lockBucket interface {
+	// tryLock stores value under key if the lock is free or has expired.
+	tryLock(key string, value []byte) (bool, error)
+	// unlock deletes key if it still holds value.
+	unlock(key string, value []byte) error
+	close()
+}
+
+// lockValue encodes the token of a lock holder and the lock's expiry.
+func lockValue(token string, expires time.Time) []byte {
+	return []byte(token + " " + strconv.FormatInt(expires.UnixMilli(), 10))
+}
+
+// lockExpired reports whether a lock value has expired. Malformed values are
+// treated as expired.
+func lockExpired(value []byte) bool {
+	_, expires, ok := bytes.Cut(value, []byte(" "))
+	if !ok {
+		return true
+	}
+	ms, err := strconv.ParseInt(string(expires), 10, 64)
+	if err != nil {
+		return true
+	}
+	return time.Now().After(time.UnixMilli(ms))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,178 @@
+package migrate
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spinframework/spin-go-sdk/v3/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// advisoryDB answers the advisory lock queries: the lock is taken once free
+// attempts have failed, and unlocking reports released.
+type advisoryDB struct {
+	busy     int
+	released bool
+	err      error
+	queries  []string
+}
+
+func (a *advisoryDB) query(query string, _ []driver.NamedValue) (driver.Rows, error) {
+	a.queries = append(a.queries, query)
+	if a.err != nil {
+		return nil, a.err
+	}
+	result := true
+	switch query {
+	case "SELECT pg_try_advisory_lock($1)":
+		if a.busy > 0 {
+			a.busy--
+			result = false
+		}
+	case "SELECT pg_advisory_unlock($1)":
+		result = a.released
+	}
+	return (&dbtest.Rows{Columns: []string{"result"}, Values: [][]driver.Value{{result}}}).Iter(), nil
+}
+
+func TestAdvisoryLock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unlocks explicitly", func(t *testing.T) {
+		fake := &advisoryDB{busy: 1, released: true}
+		db := dbtest.Open(t, &dbtest.Conn{Query: fake.query})
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+
+		unlock, err := AdvisoryLock(7).Lock(ctx, conn)
+		require.NoError(t, err)
+		require.NoError(t, unlock())
+		assert.Equal(t, []string{
+			"SELECT pg_try_advisory_lock($1)",
+			"SELECT pg_try_advisory_lock($1)",
+			"SELECT pg_advisory_unlock($1)",
+		}, fake.queries)
+
+		require.NoError(t, conn.Close())
+		assert.Equal(t, 1, db.Stats().OpenConnections)
+	})
+
+	t.Run("discards the session if unlocking fails", func(t *testing.T) {
+		fake := &advisoryDB{released: false}
+		db := dbtest.Open(t, &dbtest.Conn{Query: fake.query})
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+
+		unlock, err := AdvisoryLock(7).Lock(ctx, conn)
+		require.NoError(t, err)
+		assert.EqualError(t, unlock(), "advisory lock 7 was not held")
+		assert.Equal(t, 0, db.Stats().OpenConnections)
+	})
+
+	t.Run("discards the session if locking fails", func(t *testing.T) {
+		fake := &advisoryDB{err: errors.New("connection reset")}
+		db := dbtest.Open(t, &dbtest.Conn{Query: fake.query})
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+
+		_, err = AdvisoryLock(7).Lock(ctx, conn)
+		assert.EqualError(t, err, "connection reset")
+		assert.Equal(t, 0, db.Stats().OpenConnections)
+	})
+
+	t.Run("gives up when the context ends", func(t *testing.T) {
+		fake := &advisoryDB{busy: 1000}
+		db := dbtest.Open(t, &dbtest.Conn{Query: fake.query})
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(ctx, 2*lockRetryInterval)
+		defer cancel()
+		_, err = AdvisoryLock(7).Lock(ctx, conn)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// fakeBucket is an in-memory lockBucket.
+type fakeBucket struct {
+	values map[string][]byte
+	closed bool
+}
+
+func (b *fakeBucket) tryLock(key string, value []byte) (bool, error) {
+	if current, ok := b.values[key]; ok && !lockExpired(current) {
+		return false, nil
+	}
+	b.values[key] = value
+	return true, nil
+}
+
+func (b *fakeBucket) unlock(key string, value []byte) error {
+	if string(b.values[key]) == string(value) {
+		delete(b.values, key)
+	}
+	return nil
+}
+
+func (b *fakeBucket) close() {
+	b.closed = true
+}
+
+func TestKVLock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lock and unlock", func(t *testing.T) {
+		bucket := &fakeBucket{values: map[string][]byte{}}
+		l := &KVLock{Key: "migrate:schema_migrations"}
+
+		unlock, err := l.lock(ctx, bucket)
+		require.NoError(t, err)
+		assert.Contains(t, bucket.values, "migrate:schema_migrations")
+		assert.False(t, bucket.closed)
+
+		require.NoError(t, unlock())
+		assert.Empty(t, bucket.values)
+		assert.True(t, bucket.closed)
+	})
+
+	t.Run("waits for a held lock", func(t *testing.T) {
+		held := lockValue("other", time.Now().Add(time.Minute))
+		bucket := &fakeBucket{values: map[string][]byte{"k": held}}
+		l := &KVLock{Key: "k"}
+
+		ctx, cancel := context.WithTimeout(ctx, 2*lockRetryInterval)
+		defer cancel()
+		_, err := l.lock(ctx, bucket)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, held, bucket.values["k"])
+	})
+
+	t.Run("takes an expired lock", func(t *testing.T) {
+		bucket := &fakeBucket{values: map[string][]byte{
+			"k": lockValue("other", time.Now().Add(-time.Second)),
+		}}
+		l := &KVLock{Key: "k", TTL: time.Hour}
+
+		unlock, err := l.lock(ctx, bucket)
+		require.NoError(t, err)
+		assert.False(t, lockExpired(bucket.values["k"]))
+		require.NoError(t, unlock())
+	})
+
+	t.Run("does not release a lock taken over after expiry", func(t *testing.T) {
+		bucket := &fakeBucket{values: map[string][]byte{}}
+		l := &KVLock{Key: "k"}
+
+		unlock, err := l.lock(ctx, bucket)
+		require.NoError(t, err)
+
+		taken := lockValue("other", time.Now().Add(time.Minute))
+		bucket.values["k"] = taken
+		require.NoError(t, unlock())
+		assert.Equal(t, taken, bucket.values["k"])
+	})
+}
@@ -0,0 +1,382 @@
+// Package migrate applies versioned SQL schema migrations to Spin databases.
+//
+// Migrations are .sql files named with a version number and a description,
+// such as 0001_create_pets.sql, usually embedded in the component:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	func init() {
+//		spinhttp.Handle(func(w http.ResponseWriter, r *http.Request) {
+//			db := sqlite.Open("default")
+//			defer db.Close()
+//
+//			fsys, _ := fs.Sub(migrations, "migrations")
+//			m, err := migrate.New(db, fsys, migrate.Options{Dialect: migrate.SQLite})
+//			// if err != nil { ... }
+//
+//			applied, err := m.Up(r.Context())
+//			// if err != nil { ... }
+//		})
+//	}
+//
+// Migrations are applied in version order, and each applied migration is
+// recorded in a migrations table along with a checksum of its file. Up fails
+// if the file of an applied migration has since changed.
+//
+// Each migration runs in a transaction, unless its first line is the comment
+// "-- migrate:no-transaction", which is needed for statements such as
+// CREATE INDEX CONCURRENTLY. The Spin database hosts execute one statement
+// at a time, so migration files are split into statements on semicolons.
+//
+// While applying migrations, a Migrator holds a lock so that concurrent
+// requests do not apply the same migration twice: a session advisory lock on
+// PostgreSQL, and a lock in a key-value store for SQLite. See Options.Locker.
+package migrate
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
+)
+
+// noTransaction is the comment that opts a migration out of a transaction.
+const noTransaction = "-- migrate:no-transaction"
+
+// DefaultTable is the name of the table in which applied migrations are
+// recorded, unless Options.Table is set.
+const DefaultTable = "schema_migrations"
+
+// Migration is a versioned schema change.
+type Migration struct {
+	// Version orders the migration. It is the number at the start of the
+	// file name.
+	Version int64 `json:"version"`
+	// Name is the rest of the file name, without the extension.
+	Name string `json:"name"`
+	// Checksum is the hex-encoded SHA-256 hash of the file.
+	Checksum string `json:"checksum"`
+	// AppliedAt is when the migration was applied, or the zero time if it is
+	// pending.
+	AppliedAt time.Time `json:"applied_at,omitzero"`
+
+	script        string
+	noTransaction bool
+}
+
+// Dialect describes the SQL dialect of a database.
+type Dialect struct {
+	name        string
+	syntax      spindb.Dialect
+	placeholder func(n int) string
+	newLocker   func(table string) Locker
+}
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	return d.name
+}
+
+var (
+	// Postgres is the dialect of databases opened with pg.Open.
+	Postgres = Dialect{
+		name:        "postgres",
+		syntax:      spindb.Postgres,
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		newLocker: func(table string) Locker {
+			return AdvisoryLock(advisoryLockKey(table))
+		},
+	}
+
+	// SQLite is the dialect of databases opened with sqlite.Open.
+	SQLite = Dialect{
+		name:        "sqlite",
+		syntax:      spindb.SQLite,
+		placeholder: func(int) string { return "?" },
+		newLocker: func(table string) Locker {
+			return &KVLock{Store: "default", Key: "migrate:" + table}
+		},
+	}
+)
+
+// Options configures a Migrator.
+type Options struct {
+	// Dialect is the SQL dialect of the database. It is required.
+	Dialect Dialect
+	// Table is the name of the table in which applied migrations are
+	// recorded. It defaults to DefaultTable.
+	Table string
+	// Locker serializes migrations. It defaults to a PostgreSQL advisory lock
+	// for Postgres, and to a KVLock in the "default" key-value store for
+	// SQLite.
+	Locker Locker
+}
+
+// Migrator applies the migrations in a file system to a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	table      string
+	locker     Locker
+	migrations []Migration
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// New returns a Migrator for the migrations in the root directory of fsys.
+// It returns an error if the migrations cannot be read or are misnamed.
+func New(db *sql.DB, fsys fs.FS, opts Options) (*Migrator, error) {
+	if opts.Dialect.name == "" {
+		return nil, errors.New("migrate: no dialect")
+	}
+
+	table := cmp.Or(opts.Table, DefaultTable)
+	if !identifier.MatchString(table) {
+		return nil, fmt.Errorf("migrate: invalid table name %q", table)
+	}
+
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	locker := opts.Locker
+	if locker == nil {
+		locker = opts.Dialect.newLocker(table)
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    opts.Dialect,
+		table:      table,
+		locker:     locker,
+		migrations: migrations,
+	}, nil
+}
+
+var fileName = regexp.MustCompile(`^([0-9]+)_(.+)\.sql$`)
+
+// load reads the migrations in the root directory of fsys, ordered by
+// version.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrate: %s: file name must be <version>_<name>.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: invalid version: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+		sum := sha256.Sum256(data)
+
+		migrations = append(migrations, Migration{
+			Version:       version,
+			Name:          match[2],
+			Checksum:      hex.EncodeToString(sum[:]),
+			script:        string(data),
+			noTransaction: bytes.HasPrefix(bytes.TrimSpace(data), []byte(noTransaction)),
+		})
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrate: duplicate version %d: %s and %s",
+				migrations[i].Version, migrations[i-1].Name, migrations[i].Name)
+		}
+	}
+
+	return migrations, nil
+}
+
+// Status returns all the migrations, in version order, with AppliedAt set for
+// those that have been applied. Applied migrations whose files are missing
+// are not included.
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return m.status(ctx, conn)
+}
+
+// Pending returns the migrations that Up would apply, without applying them.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pending(status), nil
+}
+
+// Up applies the pending migrations in version order and returns those it
+// applied. If a migration fails, the migrations applied before it remain
+// applied, and the error identifies the failing statement.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	unlock, err := m.locker.Lock(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	status, err := m.status(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range pending(status) {
+		migration.AppliedAt = time.Now().UTC().Truncate(time.Second)
+		if err := m.apply(ctx, conn, migration); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+func pending(status []Migration) []Migration {
+	var migrations []Migration
+	for _, migration := range status {
+		if migration.AppliedAt.IsZero() {
+			migrations = append(migrations, migration)
+		}
+	}
+	return migrations
+}
+
+// status creates the migrations table if needed and returns the migrations
+// with the applied ones marked. It fails if an applied migration has changed.
+func (m *Migrator) status(ctx context.Context, conn *sql.Conn) ([]Migration, error) {
+	create := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version BIGINT PRIMARY KEY,
+	name TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	applied_at BIGINT NOT NULL
+)`, m.table)
+	if _, err := conn.ExecContext(ctx, create); err != nil {
+		return nil, fmt.Errorf("migrate: creating table %s: %w", m.table, err)
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, checksum, applied_at FROM %s", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("migrate: reading table %s: %w", m.table, err)
+	}
+	defer rows.Close()
+
+	type record struct {
+		checksum  string
+		appliedAt int64
+	}
+	records := map[int64]record{}
+	for rows.Next() {
+		var version int64
+		var r record
+		if err := rows.Scan(&version, &r.checksum, &r.appliedAt); err != nil {
+			return nil, fmt.Errorf("migrate: reading table %s: %w", m.table, err)
+		}
+		records[version] = r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("migrate: reading table %s: %w", m.table, err)
+	}
+
+	status := slices.Clone(m.migrations)
+	for i, migration := range status {
+		r, ok := records[migration.Version]
+		if !ok {
+			continue
+		}
+		if r.checksum != migration.Checksum {
+			return nil, fmt.Errorf("migrate: %d_%s has changed since it was applied", migration.Version, migration.Name)
+		}
+		status[i].AppliedAt = time.Unix(r.appliedAt, 0).UTC()
+	}
+
+	return status, nil
+}
+
+// apply runs a migration and records it, in a transaction unless the
+// migration opts out.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	fail := func(what string, err error) error {
+		if !migration.noTransaction {
+			conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK")
+		}
+		return fmt.Errorf("migrate: %d_%s: %s: %w", migration.Version, migration.Name, what, err)
+	}
+
+	if !migration.noTransaction {
+		if _, err := conn.ExecContext(ctx, "BEGIN"); err != nil {
+			return fail("beginning transaction", err)
+		}
+	}
+
+	for i, stmt := range splitStatements(m.dialect.syntax, migration.script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fail(fmt.Sprintf("statement %d", i+1), err)
+		}
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at) VALUES (%s)", m.table, m.placeholders(4))
+	if _, err := conn.ExecContext(ctx, insert, migration.Version, migration.Name, migration.Checksum, migration.AppliedAt.Unix()); err != nil {
+		return fail("recording migration", err)
+	}
+
+	if !migration.noTransaction {
+		if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return fail("committing transaction", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) placeholders(n int) string {
+	ps := make([]string, n)
+	for i := range ps {
+		ps[i] = m.dialect.placeholder(i + 1)
+	}
+	return strings.Join(ps, ", ")
+}
@@ -0,0 +1,294 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
+	"github.com/spinframework/spin-go-sdk/v3/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB records the statements executed against it, and keeps the
+// migrations table in memory.
+type fakeDB struct {
+	mu       sync.Mutex
+	log      []string
+	records  [][]driver.Value
+	inTx     bool
+	txRecord [][]driver.Value
+}
+
+func (db *fakeDB) exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.log = append(db.log, query)
+	switch {
+	case strings.Contains(query, "FAIL"):
+		return nil, errors.New("syntax error")
+	case query == "BEGIN":
+		db.inTx = true
+	case query == "COMMIT":
+		db.records = append(db.records, db.txRecord...)
+		db.inTx, db.txRecord = false, nil
+	case query == "ROLLBACK":
+		db.inTx, db.txRecord = false, nil
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		record := make([]driver.Value, len(args))
+		for i, arg := range args {
+			record[i] = arg.Value
+		}
+		if db.inTx {
+			db.txRecord = append(db.txRecord, record)
+		} else {
+			db.records = append(db.records, record)
+		}
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (db *fakeDB) query(query string, _ []driver.NamedValue) (driver.Rows, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if query != "SELECT version, checksum, applied_at FROM schema_migrations" {
+		return nil, errors.New("unexpected query")
+	}
+	rows := &dbtest.Rows{Columns: []string{"version", "checksum", "applied_at"}}
+	for _, record := range db.records {
+		// version, name, checksum, applied_at
+		rows.Values = append(rows.Values, []driver.Value{record[0], record[2], record[3]})
+	}
+	return rows.Iter(), nil
+}
+
+// sqliteDialect is SQLite without its default KVLock, whose wasi:keyvalue
+// imports cannot be linked into a host test binary. Tests that use it pass a
+// Locker if they get as far as locking.
+var sqliteDialect = Dialect{
+	name:        "sqlite",
+	syntax:      spindb.SQLite,
+	placeholder: func(int) string { return "?" },
+}
+
+// fakeLocker counts how often it is locked and unlocked.
+type fakeLocker struct {
+	locked, unlocked int
+}
+
+func (l *fakeLocker) Lock(context.Context, *sql.Conn) (func() error, error) {
+	l.locked++
+	return func() error {
+		l.unlocked++
+		return nil
+	}, nil
+}
+
+func openFake(t *testing.T) (*sql.DB, *fakeDB) {
+	fake := &fakeDB{}
+	return dbtest.Open(t, &dbtest.Conn{Query: fake.query, Exec: fake.exec}), fake
+}
+
+func (db *fakeDB) statements() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var stmts []string
+	for _, stmt := range db.log {
+		if !strings.HasPrefix(stmt, "CREATE TABLE IF NOT EXISTS schema_migrations") {
+			stmts = append(stmts, stmt)
+		}
+	}
+	db.log = nil
+	return stmts
+}
+
+var migrations = fstest.MapFS{
+	"0001_create_pets.sql": {Data: []byte("CREATE TABLE pets (id INT);\nCREATE TABLE toys (id INT);\n")},
+	"0002_add_name.sql":    {Data: []byte("ALTER TABLE pets ADD name TEXT;")},
+	"0010_index_names.sql": {Data: []byte("-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY pets_name ON pets (name);")},
+	"README.md":            {Data: []byte("not a migration")},
+	"seeds/0003_seeds.sql": {Data: []byte("INSERT INTO pets VALUES (1);")},
+}
+
+func versions(migrations []Migration) []int64 {
+	var vs []int64
+	for _, m := range migrations {
+		vs = append(vs, m.Version)
+	}
+	return vs
+}
+
+func TestNew(t *testing.T) {
+	db, _ := openFake(t)
+
+	m, err := New(db, migrations, Options{Dialect: sqliteDialect, Locker: &fakeLocker{}})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 10}, versions(m.migrations))
+	assert.Equal(t, "create_pets", m.migrations[0].Name)
+	assert.Len(t, m.migrations[0].Checksum, 64)
+	assert.True(t, m.migrations[2].noTransaction)
+
+	_, err = New(db, migrations, Options{})
+	assert.EqualError(t, err, "migrate: no dialect")
+
+	_, err = New(db, migrations, Options{Dialect: sqliteDialect, Table: "bad; name"})
+	assert.EqualError(t, err, `migrate: invalid table name "bad; name"`)
+
+	_, err = New(db, fstest.MapFS{"create.sql": {}}, Options{Dialect: sqliteDialect})
+	assert.EqualError(t, err, "migrate: create.sql: file name must be <version>_<name>.sql")
+
+	_, err = New(db, fstest.MapFS{"1_a.sql": {}, "01_b.sql": {}}, Options{Dialect: sqliteDialect})
+	assert.ErrorContains(t, err, "migrate: duplicate version 1: ")
+}
+
+func TestUp(t *testing.T) {
+	db, fake := openFake(t)
+	locker := &fakeLocker{}
+	ctx := context.Background()
+
+	m, err := New(db, migrations, Options{Dialect: Postgres, Locker: locker})
+	require.NoError(t, err)
+
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 10}, versions(pending))
+	assert.Empty(t, fake.statements(), "Pending must not apply migrations")
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 10}, versions(applied))
+	assert.False(t, applied[0].AppliedAt.IsZero())
+	assert.Equal(t, 1, locker.locked)
+	assert.Equal(t, 1, locker.unlocked)
+
+	insert := "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)"
+	assert.Equal(t, []string{
+		"BEGIN",
+		"CREATE TABLE pets (id INT)",
+		"CREATE TABLE toys (id INT)",
+		insert,
+		"COMMIT",
+		"BEGIN",
+		"ALTER TABLE pets ADD name TEXT",
+		insert,
+		"COMMIT",
+		"-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY pets_name ON pets (name)",
+		insert,
+	}, fake.statements())
+
+	applied, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.Empty(t, fake.statements())
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 10}, versions(status))
+	for _, migration := range status {
+		assert.False(t, migration.AppliedAt.IsZero())
+	}
+}
+
+func TestUp_Failure(t *testing.T) {
+	db, fake := openFake(t)
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"1_ok.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"2_fail.sql": {Data: []byte("CREATE TABLE b (id INT); FAIL;")},
+		"3_next.sql": {Data: []byte("CREATE TABLE c (id INT);")},
+	}
+	m, err := New(db, fsys, Options{Dialect: sqliteDialect, Locker: &fakeLocker{}})
+	require.NoError(t, err)
+
+	applied, err := m.Up(ctx)
+	assert.EqualError(t, err, "migrate: 2_fail: statement 2: syntax error")
+	assert.Equal(t, []int64{1}, versions(applied))
+	assert.Contains(t, fake.statements(), "ROLLBACK")
+
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, versions(pending))
+}
+
+func TestUp_Changed(t *testing.T) {
+	db, _ := openFake(t)
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{"1_a.sql": {Data: []byte("CREATE TABLE a (id INT);")}}
+	m, err := New(db, fsys, Options{Dialect: sqliteDialect, Locker: &fakeLocker{}})
+	require.NoError(t, err)
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	fsys["1_a.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id BIGINT);")}
+	m, err = New(db, fsys, Options{Dialect: sqliteDialect, Locker: &fakeLocker{}})
+	require.NoError(t, err)
+	_, err = m.Up(ctx)
+	assert.EqualError(t, err, "migrate: 1_a has changed since it was applied")
+}
+
+func TestHandler(t *testing.T) {
+	db, fake := openFake(t)
+
+	m, err := New(db, migrations, Options{Dialect: sqliteDialect, Locker: &fakeLocker{}})
+	require.NoError(t, err)
+	handler := m.Handler("secret")
+
+	serve := func(method, target, token string) (int, response) {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var res response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return rec.Code, res
+	}
+
+	code, _ := serve(http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = serve(http.MethodGet, "/", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = serve(http.MethodDelete, "/", "secret")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+
+	code, res := serve(http.MethodPost, "/?dry_run=true", "secret")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []int64{1, 2, 10}, versions(res.Migrations))
+	assert.Empty(t, fake.statements())
+
+	code, res = serve(http.MethodPost, "/", "secret")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []int64{1, 2, 10}, versions(res.Migrations))
+
+	code, res = serve(http.MethodGet, "/", "secret")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Migrations, 3)
+	assert.False(t, res.Migrations[0].AppliedAt.IsZero())
+
+	rec := httptest.NewRecorder()
+	m.Handler("").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestLockExpired(t *testing.T) {
+	assert.True(t, lockExpired([]byte("garbage")))
+	assert.True(t, lockExpired(lockValue("a", time.Now().Add(-1))))
+	assert.False(t, lockExpired(lockValue("a", time.Now().Add(1e9))))
+}
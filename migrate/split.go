@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"strings"
+
+	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
+)
+
+// splitStatements splits a migration script in the given syntax into
+// statements, since the Spin database hosts execute a single statement at a
+// time. Statements are separated by semicolons outside of string literals,
+// quoted identifiers, comments, PostgreSQL dollar-quoted bodies and the
+// BEGIN ... END blocks of CREATE TRIGGER and similar statements. Empty
+// statements are dropped.
+func splitStatements(syntax spindb.Dialect, script string) []string {
+	var statements []string
+	start := 0
+	// depth counts the open BEGIN and CASE blocks of a CREATE statement
+	depth := 0
+	// creating is whether the current statement starts with CREATE
+	creating := false
+	// words is the number of words seen in the current statement
+	words := 0
+
+	emit := func(end int) {
+		if stmt := strings.TrimSpace(script[start:end]); stmt != "" && !onlyComments(syntax, stmt) {
+			statements = append(statements, stmt)
+		}
+		start = end + 1
+		depth, creating, words = 0, false, 0
+	}
+
+	for i := 0; i < len(script); {
+		if j := syntax.Skip(script, i); j > i {
+			i = j
+			continue
+		}
+		switch c := script[i]; {
+		case c == ';':
+			if depth == 0 {
+				emit(i)
+			}
+			i++
+		case isWordChar(c):
+			j := i
+			for j < len(script) && (isWordChar(script[j]) || script[j] == '$') {
+				j++
+			}
+			word := strings.ToUpper(script[i:j])
+			if words == 0 {
+				creating = word == "CREATE"
+			}
+			words++
+			if creating {
+				switch word {
+				case "BEGIN", "CASE":
+					depth++
+				case "END":
+					if depth > 0 {
+						depth--
+					}
+				}
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	emit(len(script))
+
+	return statements
+}
+
+// onlyComments reports whether stmt consists only of comments.
+func onlyComments(syntax spindb.Dialect, stmt string) bool {
+	for i := 0; i < len(stmt); {
+		switch {
+		case strings.HasPrefix(stmt[i:], "--"), strings.HasPrefix(stmt[i:], "/*"):
+			i = syntax.Skip(stmt, i)
+		case strings.ContainsRune(" \t\r\n", rune(stmt[i])):
+			i++
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
@@ -0,0 +1,94 @@
+package migrate
+
+import (
+	"testing"
+
+	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		syntax spindb.Dialect
+		script string
+		want   []string
+	}{
+		{
+			name:   "single",
+			script: "CREATE TABLE pets (id INT)",
+			want:   []string{"CREATE TABLE pets (id INT)"},
+		},
+		{
+			name:   "multiple",
+			script: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "semicolons in strings and identifiers",
+			syntax: spindb.SQLite,
+			script: `INSERT INTO "a;b" VALUES ('x;y', 'it''s;'); SELECT [c;d], ` + "`e;f`",
+			want:   []string{`INSERT INTO "a;b" VALUES ('x;y', 'it''s;')`, "SELECT [c;d], `e;f`"},
+		},
+		{
+			name:   "comments",
+			script: "-- first; table\nCREATE TABLE a (id INT); /* b; */\n-- trailing;\n",
+			want:   []string{"-- first; table\nCREATE TABLE a (id INT)"},
+		},
+		{
+			name: "dollar quoted",
+			script: `CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql;
+CREATE FUNCTION g() RETURNS int AS $$ SELECT 2; $$ LANGUAGE sql;
+SELECT $1;`,
+			want: []string{
+				`CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql`,
+				`CREATE FUNCTION g() RETURNS int AS $$ SELECT 2; $$ LANGUAGE sql`,
+				`SELECT $1`,
+			},
+		},
+		{
+			name:   "escape string",
+			script: `INSERT INTO a VALUES (E'it\'s; x', e'\\'); SELECT 'a\'; SELECT 1;`,
+			want:   []string{`INSERT INTO a VALUES (E'it\'s; x', e'\\')`, `SELECT 'a\'`, `SELECT 1`},
+		},
+		{
+			name:   "nested comments",
+			script: "/* a /* b; */ c; */ CREATE TABLE a (id INT); SELECT 1;",
+			want:   []string{"/* a /* b; */ c; */ CREATE TABLE a (id INT)", "SELECT 1"},
+		},
+		{
+			name:   "SQLite comments do not nest",
+			syntax: spindb.SQLite,
+			script: "/* a /* b */ CREATE TABLE a (id INT); SELECT $a$;",
+			want:   []string{"/* a /* b */ CREATE TABLE a (id INT)", "SELECT $a$"},
+		},
+		{
+			name: "trigger",
+			script: `CREATE TRIGGER t AFTER INSERT ON a BEGIN
+	UPDATE b SET n = CASE WHEN n IS NULL THEN 1 ELSE n + 1 END;
+	DELETE FROM c;
+END;
+INSERT INTO a VALUES (1);`,
+			want: []string{
+				"CREATE TRIGGER t AFTER INSERT ON a BEGIN\n\tUPDATE b SET n = CASE WHEN n IS NULL THEN 1 ELSE n + 1 END;\n\tDELETE FROM c;\nEND",
+				"INSERT INTO a VALUES (1)",
+			},
+		},
+		{
+			name:   "begin outside create",
+			script: "BEGIN; SELECT 1; END;",
+			want:   []string{"BEGIN", "SELECT 1", "END"},
+		},
+		{
+			name:   "empty",
+			script: " ;\n; -- nothing\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStatements(tt.syntax, tt.script))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxInFlight is the number of publishes PublishBatch issues
+	// concurrently when BatchOptions.MaxInFlight is zero.
+	DefaultMaxInFlight = 16
+	// DefaultMaxAttempts is the number of times PublishBatch tries to publish
+	// a message when BatchOptions.MaxAttempts is zero.
+	DefaultMaxAttempts = 3
+	// DefaultBackoff is the initial delay between attempts when
+	// BatchOptions.Backoff is zero.
+	DefaultBackoff = 100 * time.Millisecond
+	// DefaultMaxBackoff is the maximum delay between attempts when
+	// BatchOptions.MaxBackoff is zero.
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Message is an MQTT message to publish.
+type Message struct {
+	Topic   string
+	Payload []byte
+	QoS     QoS
+}
+
+// BatchOptions configures PublishBatch.
+type BatchOptions struct {
+	// MaxInFlight is the maximum number of publishes in flight at once. It
+	// defaults to DefaultMaxInFlight.
+	MaxInFlight int
+	// MaxAttempts is the maximum number of times a message is tried. It
+	// defaults to DefaultMaxAttempts; set it to 1 to disable retries.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, which doubles for each
+	// further retry up to MaxBackoff. Each delay is jittered by choosing it
+	// at random between zero and its nominal value. They default to
+	// DefaultBackoff and DefaultMaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// PublishResult is the outcome of publishing one message of a batch.
+type PublishResult struct {
+	// Err is the error of the last attempt, or nil if the message was
+	// published.
+	Err error
+	// Attempts is the number of times the message was tried.
+	Attempts int
+}
+
+// PublishBatch publishes messages concurrently, with at most
+// opts.MaxInFlight publishes in flight at once, and returns the result of each
+// in the order of messages. It returns the first error among the messages,
+// in that order.
+//
+// Messages with QoS QosAtLeastOnce or QosExactlyOnce are retried with
+// jittered exponential backoff when they fail with ErrConnectionFailed.
+// Messages with QoS QosAtMostOnce are never retried. Messages are not
+// guaranteed to be published in order, even to the same topic.
+func (c *Connection) PublishBatch(messages []Message, opts BatchOptions) ([]PublishResult, error) {
+	return publishBatch(func(m Message) error {
+		return c.Publish(m.Topic, m.Payload, m.QoS)
+	}, messages, opts, time.Sleep)
+}
+
+func publishBatch(publish func(Message) error, messages []Message, opts BatchOptions, sleep func(time.Duration)) ([]PublishResult, error) {
+	maxInFlight := orDefault(opts.MaxInFlight, DefaultMaxInFlight)
+	maxAttempts := orDefault(opts.MaxAttempts, DefaultMaxAttempts)
+	backoff := orDefault(opts.Backoff, DefaultBackoff)
+	maxBackoff := orDefault(opts.MaxBackoff, DefaultMaxBackoff)
+
+	results := make([]PublishResult, len(messages))
+	inFlight := make(chan struct{}, maxInFlight)
+
+	var wg sync.WaitGroup
+	for i, m := range messages {
+		inFlight <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-inFlight }()
+
+			result := &results[i]
+			delay := backoff
+			for {
+				result.Attempts++
+				result.Err = publish(m)
+				if result.Err == nil || !retryable(m, result.Err) || result.Attempts >= maxAttempts {
+					break
+				}
+				sleep(rand.N(delay + 1))
+				delay = min(2*delay, maxBackoff)
+			}
+		})
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if result.Err != nil {
+			return results, fmt.Errorf("mqtt: publishing message %d to %s: %w", i, messages[i].Topic, result.Err)
+		}
+	}
+	return results, nil
+}
+
+// retryable reports whether a message that failed with err may be retried.
+func retryable(m Message, err error) bool {
+	return m.QoS != QosAtMostOnce && errors.Is(err, ErrConnectionFailed)
+}
+
+// orDefault returns v, or def if v is not positive.
+func orDefault[T int | time.Duration](v, def T) T {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,101 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishBatch(t *testing.T) {
+	connectionFailed := fmt.Errorf("%w: broker unavailable", ErrConnectionFailed)
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	publish := func(m Message) error {
+		mu.Lock()
+		calls[m.Topic]++
+		n := calls[m.Topic]
+		mu.Unlock()
+
+		switch m.Topic {
+		case "flaky":
+			if n < 3 {
+				return connectionFailed
+			}
+		case "down", "fire-and-forget":
+			return connectionFailed
+		case "invalid":
+			return ErrInvalidAddress
+		}
+		return nil
+	}
+
+	var delaysMu sync.Mutex
+	var delays []time.Duration
+	sleep := func(d time.Duration) {
+		delaysMu.Lock()
+		delays = append(delays, d)
+		delaysMu.Unlock()
+	}
+
+	messages := []Message{
+		{Topic: "ok", QoS: QosAtLeastOnce},
+		{Topic: "flaky", QoS: QosExactlyOnce},
+		{Topic: "down", QoS: QosAtLeastOnce},
+		{Topic: "fire-and-forget", QoS: QosAtMostOnce},
+		{Topic: "invalid", QoS: QosAtLeastOnce},
+	}
+	results, err := publishBatch(publish, messages, BatchOptions{MaxAttempts: 4, Backoff: time.Second, MaxBackoff: 2 * time.Second}, sleep)
+
+	require.Len(t, results, 5)
+	assert.Equal(t, PublishResult{Attempts: 1}, results[0])
+	assert.Equal(t, PublishResult{Attempts: 3}, results[1])
+	assert.Equal(t, PublishResult{Err: connectionFailed, Attempts: 4}, results[2])
+	assert.Equal(t, PublishResult{Err: connectionFailed, Attempts: 1}, results[3])
+	assert.Equal(t, PublishResult{Err: ErrInvalidAddress, Attempts: 1}, results[4])
+
+	assert.EqualError(t, err, "mqtt: publishing message 2 to down: mqtt: connection failed: broker unavailable")
+	assert.ErrorIs(t, err, ErrConnectionFailed)
+
+	// 2 retries of "flaky" and 3 of "down", each jittered within its backoff.
+	assert.Len(t, delays, 5)
+	for _, d := range delays {
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, 2*time.Second)
+	}
+}
+
+func TestPublishBatchInFlight(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	publish := func(Message) error {
+		n := inFlight.Add(1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		inFlight.Add(-1)
+		return nil
+	}
+
+	messages := make([]Message, 50)
+	results, err := publishBatch(publish, messages, BatchOptions{MaxInFlight: 4}, nil)
+	require.NoError(t, err)
+	assert.Len(t, results, 50)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(4))
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+}
+
+func TestPublishBatchEmpty(t *testing.T) {
+	results, err := publishBatch(func(Message) error { return errors.New("unexpected") }, nil, BatchOptions{}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -2,17 +2,71 @@
 package mqtt
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	mqtt "github.com/spinframework/spin-go-sdk/v3/imports/spin_mqtt_3_0_0_mqtt"
 )
 
+var (
+	// ErrInvalidAddress is returned when the broker address is invalid.
+	ErrInvalidAddress = errors.New("mqtt: invalid address")
+	// ErrTooManyConnections is returned when the component has too many open
+	// connections.
+	ErrTooManyConnections = errors.New("mqtt: too many connections")
+	// ErrConnectionFailed is wrapped by errors returned when connecting to
+	// the broker fails, for example because the address is not allowed.
+	ErrConnectionFailed = errors.New("mqtt: connection failed")
+	// ErrOther is wrapped by any other error reported by the host.
+	ErrOther = errors.New("mqtt: error")
+)
+
+// DefaultKeepAlive is the keep-alive interval used when Options.KeepAlive is
+// zero.
+const DefaultKeepAlive = 30 * time.Second
+
+// Options configures a connection.
+type Options struct {
+	// Username and Password authenticate with the broker, if set.
+	Username string
+	Password string
+	// KeepAlive is the maximum interval between messages sent to the broker,
+	// rounded down to whole seconds. It defaults to DefaultKeepAlive.
+	KeepAlive time.Duration
+}
+
 // Connection represents an MQTT connection.
 type Connection struct {
 	conn mqtt.Connection
 }
 
+// Dial opens a new MQTT connection to the broker at address, such as
+// "mqtt://localhost:1883".
+func Dial(address string, opts Options) (Connection, error) {
+	keepAlive, err := keepAliveSecs(opts.KeepAlive)
+	if err != nil {
+		return Connection{}, err
+	}
+
+	return OpenConnection(address, opts.Username, opts.Password, keepAlive)
+}
+
+// keepAliveSecs returns the keep-alive interval in whole seconds, defaulting
+// to DefaultKeepAlive.
+func keepAliveSecs(keepAlive time.Duration) (uint64, error) {
+	if keepAlive == 0 {
+		keepAlive = DefaultKeepAlive
+	}
+	if keepAlive < time.Second {
+		return 0, fmt.Errorf("mqtt: keep-alive interval %v is less than a second", keepAlive)
+	}
+	return uint64(keepAlive / time.Second), nil
+}
+
 // OpenConnection opens a new MQTT connection to the specified address.
+//
+// Deprecated: Use Dial, which takes the connection options as a struct.
 func OpenConnection(address, username, password string, keepAliveIntervalInSecs uint64) (Connection, error) {
 	result := mqtt.ConnectionOpen(address, username, password, keepAliveIntervalInSecs)
 	if result.IsErr() {
@@ -46,9 +100,15 @@ const (
 
 func toError(err mqtt.Error) error {
 	switch err.Tag() {
+	case mqtt.ErrorInvalidAddress:
+		return ErrInvalidAddress
+	case mqtt.ErrorTooManyConnections:
+		return ErrTooManyConnections
 	case mqtt.ErrorConnectionFailed:
-		return fmt.Errorf("connection-failed: %s", err.ConnectionFailed())
+		return fmt.Errorf("%w: %s", ErrConnectionFailed, err.ConnectionFailed())
+	case mqtt.ErrorOther:
+		return fmt.Errorf("%w: %s", ErrOther, err.Other())
 	default:
-		return fmt.Errorf("%s", err.Other())
+		return fmt.Errorf("%w: unknown error from runtime (tag %d)", ErrOther, err.Tag())
 	}
 }
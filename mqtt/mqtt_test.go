@@ -0,0 +1,167 @@
+package mqtt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mqtt "github.com/spinframework/spin-go-sdk/v3/imports/spin_mqtt_3_0_0_mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToError(t *testing.T) {
+	assert.ErrorIs(t, toError(mqtt.MakeErrorInvalidAddress()), ErrInvalidAddress)
+	assert.ErrorIs(t, toError(mqtt.MakeErrorTooManyConnections()), ErrTooManyConnections)
+
+	err := toError(mqtt.MakeErrorConnectionFailed("address not allowed"))
+	assert.ErrorIs(t, err, ErrConnectionFailed)
+	assert.EqualError(t, err, "mqtt: connection failed: address not allowed")
+
+	err = toError(mqtt.MakeErrorOther("broker went away"))
+	assert.ErrorIs(t, err, ErrOther)
+	assert.EqualError(t, err, "mqtt: error: broker went away")
+}
+
+func TestKeepAliveSecs(t *testing.T) {
+	secs, err := keepAliveSecs(0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(30), secs)
+
+	secs, err = keepAliveSecs(90*time.Second + 500*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(90), secs)
+
+	_, err = keepAliveSecs(500 * time.Millisecond)
+	assert.EqualError(t, err, "mqtt: keep-alive interval 500ms is less than a second")
+
+	_, err = keepAliveSecs(-time.Second)
+	assert.Error(t, err)
+}
+
+func TestTopic(t *testing.T) {
+	tests := []struct {
+		template string
+		params   map[string]string
+		want     string
+		wantErr  string
+	}{{
+		template: "devices/{id}/telemetry",
+		params:   map[string]string{"id": "d1"},
+		want:     "devices/d1/telemetry",
+	}, {
+		template: "{site}/{id}",
+		params:   map[string]string{"site": "north", "id": "d1", "unused": "x"},
+		want:     "north/d1",
+	}, {
+		template: "status",
+		want:     "status",
+	}, {
+		template: "devices/{id}/telemetry",
+		wantErr:  `mqtt: no value for {id} in topic "devices/{id}/telemetry"`,
+	}, {
+		template: "devices/{id}",
+		params:   map[string]string{"id": "a/b"},
+		wantErr:  `mqtt: invalid value "a/b" for {id} in topic "devices/{id}"`,
+	}, {
+		template: "devices/{id}",
+		params:   map[string]string{"id": "#"},
+		wantErr:  `mqtt: invalid value "#" for {id} in topic "devices/{id}"`,
+	}, {
+		template: "devices/{id}",
+		params:   map[string]string{"id": ""},
+		wantErr:  `mqtt: invalid value "" for {id} in topic "devices/{id}"`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			topic, err := ParseTopic(tt.template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.template, topic.String())
+
+			got, err := topic.Expand(tt.params)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTopicErrors(t *testing.T) {
+	tests := map[string]string{
+		"":                "mqtt: empty topic",
+		"devices/+/data":  `mqtt: topic "devices/+/data" contains a wildcard`,
+		"devices/#":       `mqtt: topic "devices/#" contains a wildcard`,
+		"devices/{id":     `mqtt: topic "devices/{id" has an unmatched '{'`,
+		"devices/id}":     `mqtt: topic "devices/id}" has an unmatched '}'`,
+		"devices/{}":      `mqtt: topic "devices/{}" has an invalid placeholder {}`,
+		"devices/{a{b}}":  `mqtt: topic "devices/{a{b}}" has an invalid placeholder {a{b}`,
+		"devices/{a/b}/x": `mqtt: topic "devices/{a/b}/x" has an invalid placeholder {a/b}`,
+	}
+
+	for template, wantErr := range tests {
+		t.Run(template, func(t *testing.T) {
+			_, err := ParseTopic(template)
+			assert.EqualError(t, err, wantErr)
+		})
+	}
+}
+
+type binary struct{}
+
+func (binary) MarshalBinary() ([]byte, error) { return []byte{1, 2}, nil }
+
+type message struct {
+	Name string
+}
+
+func TestEncoders(t *testing.T) {
+	b, err := JSON(map[string]int{"a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(b))
+
+	b, err = Raw("hello")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), b)
+
+	b, err = Raw([]byte{0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1}, b)
+
+	b, err = Raw(binary{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, b)
+
+	_, err = Raw(42)
+	assert.EqualError(t, err, "mqtt: cannot encode int as raw bytes")
+
+	proto := Protobuf(func(m *message) ([]byte, error) { return []byte(m.Name), nil })
+	b, err = proto(&message{Name: "m"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("m"), b)
+
+	_, err = proto(message{})
+	assert.EqualError(t, err, "mqtt: cannot encode mqtt.message as a protocol buffer")
+}
+
+func TestPublisherMessage(t *testing.T) {
+	_, err := NewPublisher(nil, "devices/+", PublisherOptions{})
+	assert.Error(t, err)
+
+	p, err := NewPublisher(nil, "devices/{id}/telemetry", PublisherOptions{})
+	require.NoError(t, err)
+
+	topic, payload, err := p.message(message{Name: "m"}, map[string]string{"id": "d1"})
+	require.NoError(t, err)
+	assert.Equal(t, "devices/d1/telemetry", topic)
+	assert.JSONEq(t, `{"Name":"m"}`, string(payload))
+
+	p, err = NewPublisher(nil, "devices/{id}", PublisherOptions{Encoder: Raw})
+	require.NoError(t, err)
+	_, _, err = p.message(42, map[string]string{"id": "d1"})
+	assert.EqualError(t, err, "mqtt: encoding message for devices/d1: mqtt: cannot encode int as raw bytes")
+	assert.False(t, errors.Is(err, ErrOther))
+}
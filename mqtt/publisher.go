@@ -0,0 +1,183 @@
+package mqtt
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Encoder encodes a value as a message payload.
+type Encoder func(v any) ([]byte, error)
+
+// JSON is an Encoder that encodes values as JSON.
+func JSON(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Raw is an Encoder that publishes []byte and string values as they are, and
+// values implementing encoding.BinaryMarshaler in their binary form.
+func Raw(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case encoding.BinaryMarshaler:
+		return v.MarshalBinary()
+	default:
+		return nil, fmt.Errorf("mqtt: cannot encode %T as raw bytes", v)
+	}
+}
+
+// Protobuf returns an Encoder that encodes protocol buffer messages with
+// marshal, which is normally proto.Marshal:
+//
+//	pub, err := mqtt.NewPublisher(&conn, "devices/{id}/telemetry", mqtt.PublisherOptions{
+//		Encoder: mqtt.Protobuf(proto.Marshal),
+//	})
+//
+// Values that are not of type M are rejected.
+func Protobuf[M any](marshal func(M) ([]byte, error)) Encoder {
+	return func(v any) ([]byte, error) {
+		m, ok := v.(M)
+		if !ok {
+			return nil, fmt.Errorf("mqtt: cannot encode %T as a protocol buffer", v)
+		}
+		return marshal(m)
+	}
+}
+
+// Topic is a topic name template, such as "devices/{id}/telemetry", whose
+// {name} placeholders are filled in when publishing.
+type Topic struct {
+	template string
+	// parts alternates literal text and placeholder names, starting with
+	// literal text
+	parts []string
+}
+
+// ParseTopic parses a topic template. Placeholders must be non-empty names in
+// braces, and the template must not contain the wildcards "+" and "#", which
+// are not allowed in the topics messages are published to.
+func ParseTopic(template string) (*Topic, error) {
+	if template == "" {
+		return nil, fmt.Errorf("mqtt: empty topic")
+	}
+	if strings.ContainsAny(template, "+#") {
+		return nil, fmt.Errorf("mqtt: topic %q contains a wildcard", template)
+	}
+
+	t := &Topic{template: template}
+	rest := template
+	for {
+		literal, after, found := strings.Cut(rest, "{")
+		if strings.Contains(literal, "}") {
+			return nil, fmt.Errorf("mqtt: topic %q has an unmatched '}'", template)
+		}
+		t.parts = append(t.parts, literal)
+		if !found {
+			return t, nil
+		}
+
+		name, after, found := strings.Cut(after, "}")
+		if !found {
+			return nil, fmt.Errorf("mqtt: topic %q has an unmatched '{'", template)
+		}
+		if name == "" || strings.ContainsAny(name, "{/") {
+			return nil, fmt.Errorf("mqtt: topic %q has an invalid placeholder {%s}", template, name)
+		}
+		t.parts = append(t.parts, name)
+		rest = after
+	}
+}
+
+// String returns the template.
+func (t *Topic) String() string {
+	return t.template
+}
+
+// Expand returns the topic name with each placeholder replaced by its value in
+// params. Values must be non-empty and must not contain "/", "+" or "#", so
+// that they fill exactly one topic level.
+func (t *Topic) Expand(params map[string]string) (string, error) {
+	var b strings.Builder
+	for i, part := range t.parts {
+		if i%2 == 0 {
+			b.WriteString(part)
+			continue
+		}
+		value, ok := params[part]
+		if !ok {
+			return "", fmt.Errorf("mqtt: no value for {%s} in topic %q", part, t.template)
+		}
+		if value == "" || strings.ContainsAny(value, "/+#") {
+			return "", fmt.Errorf("mqtt: invalid value %q for {%s} in topic %q", value, part, t.template)
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// PublisherOptions configures a Publisher.
+type PublisherOptions struct {
+	// Encoder encodes published values. It defaults to JSON.
+	Encoder Encoder
+	// QoS is the quality of service of published messages. It defaults to
+	// QosAtMostOnce.
+	QoS QoS
+}
+
+// Publisher publishes values to the topics of a topic template:
+//
+//	pub, err := mqtt.NewPublisher(&conn, "devices/{id}/telemetry", mqtt.PublisherOptions{
+//		QoS: mqtt.QosAtLeastOnce,
+//	})
+//	// if err != nil { ... }
+//
+//	err = pub.Publish(reading, map[string]string{"id": deviceID})
+type Publisher struct {
+	conn    *Connection
+	topic   *Topic
+	encoder Encoder
+	qos     QoS
+}
+
+// NewPublisher returns a Publisher that publishes on conn to the topics of the
+// template topic. See ParseTopic for the template syntax.
+func NewPublisher(conn *Connection, topic string, opts PublisherOptions) (*Publisher, error) {
+	t, err := ParseTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := opts.Encoder
+	if encoder == nil {
+		encoder = JSON
+	}
+
+	return &Publisher{conn: conn, topic: t, encoder: encoder, qos: opts.QoS}, nil
+}
+
+// Publish encodes v and publishes it to the topic given by expanding the
+// template with params.
+func (p *Publisher) Publish(v any, params map[string]string) error {
+	topic, payload, err := p.message(v, params)
+	if err != nil {
+		return err
+	}
+	return p.conn.Publish(topic, payload, p.qos)
+}
+
+// message returns the topic and payload of a message.
+func (p *Publisher) message(v any, params map[string]string) (string, []byte, error) {
+	topic, err := p.topic.Expand(params)
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := p.encoder(v)
+	if err != nil {
+		return "", nil, fmt.Errorf("mqtt: encoding message for %s: %w", topic, err)
+	}
+	return topic, payload, nil
+}
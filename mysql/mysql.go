@@ -8,6 +8,7 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"time"
 
 	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
 	mysql "github.com/spinframework/spin-go-sdk/v3/imports/fermyon_spin_2_0_0_mysql"
@@ -35,15 +36,55 @@ func (c *conn) Begin() (driver.Tx, error) {
 	return nil, errors.New("transactions are unsupported by this driver")
 }
 
+// QueryContext executes a query that may return rows, such as a SELECT.
+//
+// The MySQL host interface is synchronous, so ctx is only checked before the
+// query is sent.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.query(query, spindb.NamedValues(args))
+}
+
+// ExecContext executes a query that doesn't return rows, such as an INSERT or
+// UPDATE.
+//
+// The MySQL host interface is synchronous, so ctx is only checked before the
+// statement is sent.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.exec(query, spindb.NamedValues(args))
+}
+
+// CheckNamedValue converts a query argument to a MySQL parameter value,
+// returning a *ConversionError if its type is not supported.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return spindb.CheckNamedValue(nv, toWasiParameterValue)
+}
+
+var _ driver.NamedValueChecker = (*conn)(nil)
+var _ driver.QueryerContext = (*conn)(nil)
+var _ driver.ExecerContext = (*conn)(nil)
+
+// ConversionError is returned when a query argument cannot be converted to a
+// MySQL parameter value.
+type ConversionError = spindb.ConversionError
+
 type connector struct {
 	conn *conn
 	name string
 }
 
-func (d *connector) Connect(_ context.Context) (driver.Conn, error) {
+func (d *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	if d.conn != nil {
 		return d.conn, nil
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return d.Open(d.name)
 }
 
@@ -135,7 +176,8 @@ type stmt struct {
 }
 
 var _ driver.Stmt = (*stmt)(nil)
-var _ driver.ColumnConverter = (*stmt)(nil)
+var _ driver.StmtQueryContext = (*stmt)(nil)
+var _ driver.StmtExecContext = (*stmt)(nil)
 
 // Close closes the statement.
 func (s *stmt) Close() error {
@@ -151,12 +193,32 @@ func (s *stmt) NumInput() int {
 // Exec executes a query that doesn't return rows, such as an INSERT or
 // UPDATE.
 func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
-	wasiParams := make([]mysql.ParameterValue, len(args))
-	for i, v := range args {
-		wasiParams[i] = toWasiParameterValue(v)
+	return s.conn.exec(s.query, args)
+}
+
+// Query executes a query that may return rows, such as a SELECT.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.query(s.query, args)
+}
+
+// ExecContext executes a query that doesn't return rows, such as an INSERT or
+// UPDATE.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+// QueryContext executes a query that may return rows, such as a SELECT.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func (c *conn) exec(query string, args []driver.Value) (driver.Result, error) {
+	wasiParams, err := toWasiParameterValues(args)
+	if err != nil {
+		return nil, err
 	}
 
-	queryResult := s.conn.spinConn.Execute(s.query, wasiParams)
+	queryResult := c.spinConn.Execute(query, wasiParams)
 	if queryResult.IsErr() {
 		return &result{}, toError(queryResult.Err())
 	}
@@ -164,14 +226,13 @@ func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
 	return &result{}, nil
 }
 
-// Query executes a query that may return rows, such as a SELECT.
-func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
-	wasiParams := make([]mysql.ParameterValue, len(args))
-	for i, v := range args {
-		wasiParams[i] = toWasiParameterValue(v)
+func (c *conn) query(query string, args []driver.Value) (driver.Rows, error) {
+	wasiParams, err := toWasiParameterValues(args)
+	if err != nil {
+		return nil, err
 	}
 
-	results := s.conn.spinConn.Query(s.query, wasiParams)
+	results := c.spinConn.Query(query, wasiParams)
 	if results.IsErr() {
 		return nil, toError(results.Err())
 	}
@@ -199,43 +260,69 @@ func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
 	return rows, nil
 }
 
-func toWasiParameterValue(x any) mysql.ParameterValue {
+func toWasiParameterValues(args []driver.Value) ([]mysql.ParameterValue, error) {
+	wasiParams := make([]mysql.ParameterValue, len(args))
+	for i, v := range args {
+		p, err := toWasiParameterValue(v)
+		if err != nil {
+			return nil, err
+		}
+		wasiParams[i] = p
+	}
+	return wasiParams, nil
+}
+
+func toWasiParameterValue(x any) (mysql.ParameterValue, error) {
 	switch v := x.(type) {
+	case mysql.ParameterValue:
+		return v, nil
 	case bool:
-		return rdbmstypes.MakeParameterValueBoolean(v)
+		return rdbmstypes.MakeParameterValueBoolean(v), nil
 	case int8:
-		return rdbmstypes.MakeParameterValueInt8(v)
+		return rdbmstypes.MakeParameterValueInt8(v), nil
 	case int16:
-		return rdbmstypes.MakeParameterValueInt16(v)
+		return rdbmstypes.MakeParameterValueInt16(v), nil
 	case int32:
-		return rdbmstypes.MakeParameterValueInt32(v)
+		return rdbmstypes.MakeParameterValueInt32(v), nil
 	case int64:
-		return rdbmstypes.MakeParameterValueInt64(v)
+		return rdbmstypes.MakeParameterValueInt64(v), nil
 	case int:
-		return rdbmstypes.MakeParameterValueInt64(int64(v))
+		return rdbmstypes.MakeParameterValueInt64(int64(v)), nil
 	case uint8:
-		return rdbmstypes.MakeParameterValueUint8(v)
+		return rdbmstypes.MakeParameterValueUint8(v), nil
 	case uint16:
-		return rdbmstypes.MakeParameterValueUint16(v)
+		return rdbmstypes.MakeParameterValueUint16(v), nil
 	case uint32:
-		return rdbmstypes.MakeParameterValueUint32(v)
+		return rdbmstypes.MakeParameterValueUint32(v), nil
 	case uint64:
-		return rdbmstypes.MakeParameterValueUint64(v)
+		return rdbmstypes.MakeParameterValueUint64(v), nil
 	case float32:
-		return rdbmstypes.MakeParameterValueFloating32(v)
+		return rdbmstypes.MakeParameterValueFloating32(v), nil
 	case float64:
-		return rdbmstypes.MakeParameterValueFloating64(v)
+		return rdbmstypes.MakeParameterValueFloating64(v), nil
 	case string:
-		return rdbmstypes.MakeParameterValueStr(v)
+		return rdbmstypes.MakeParameterValueStr(v), nil
 	case []byte:
-		return rdbmstypes.MakeParameterValueBinary(v)
+		return rdbmstypes.MakeParameterValueBinary(v), nil
+	case time.Time:
+		return rdbmstypes.MakeParameterValueStr(v.UTC().Format(timeFormat)), nil
 	case nil:
-		return rdbmstypes.MakeParameterValueDbNull()
-	default:
-		panic("unknown value type")
+		return rdbmstypes.MakeParameterValueDbNull(), nil
 	}
+
+	v, ok, err := spindb.Indirect(x)
+	if err != nil {
+		return mysql.ParameterValue{}, err
+	}
+	if !ok {
+		return mysql.ParameterValue{}, &ConversionError{Value: x}
+	}
+	return toWasiParameterValue(v)
 }
 
+// timeFormat is the layout MySQL accepts for DATETIME and TIMESTAMP values.
+const timeFormat = "2006-01-02 15:04:05.999999"
+
 func toError(err mysql.Error) error {
 	switch err.Tag() {
 	case rdbmstypes.ErrorBadParameter:
@@ -292,11 +379,6 @@ func toRow(row []rdbmstypes.DbValue) []any {
 	return result
 }
 
-// ColumnConverter returns GlobalParameterConverter to prevent using driver.DefaultParameterConverter.
-func (s *stmt) ColumnConverter(_ int) driver.ValueConverter {
-	return spindb.GlobalParameterConverter
-}
-
 type result struct{}
 
 func (r result) LastInsertId() (int64, error) {
@@ -0,0 +1,165 @@
+package mysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	rdbmstypes "github.com/spinframework/spin-go-sdk/v3/imports/fermyon_spin_2_0_0_rdbms_types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedInt int
+
+type brokenValuer struct{}
+
+func (brokenValuer) Value() (driver.Value, error) {
+	return nil, errors.New("broken")
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestToWasiParameterValue(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want rdbmstypes.ParameterValue
+	}{{
+		name: "bool",
+		src:  true,
+		want: rdbmstypes.MakeParameterValueBoolean(true),
+	}, {
+		name: "int",
+		src:  42,
+		want: rdbmstypes.MakeParameterValueInt64(42),
+	}, {
+		name: "int8",
+		src:  int8(-8),
+		want: rdbmstypes.MakeParameterValueInt8(-8),
+	}, {
+		name: "uint64",
+		src:  uint64(1 << 63),
+		want: rdbmstypes.MakeParameterValueUint64(1 << 63),
+	}, {
+		name: "float32",
+		src:  float32(1.5),
+		want: rdbmstypes.MakeParameterValueFloating32(1.5),
+	}, {
+		name: "string",
+		src:  "hello",
+		want: rdbmstypes.MakeParameterValueStr("hello"),
+	}, {
+		name: "bytes",
+		src:  []byte{1, 2},
+		want: rdbmstypes.MakeParameterValueBinary([]byte{1, 2}),
+	}, {
+		name: "nil",
+		src:  nil,
+		want: rdbmstypes.MakeParameterValueDbNull(),
+	}, {
+		name: "named int",
+		src:  namedInt(7),
+		want: rdbmstypes.MakeParameterValueInt64(7),
+	}, {
+		name: "pointer",
+		src:  ptr("hello"),
+		want: rdbmstypes.MakeParameterValueStr("hello"),
+	}, {
+		name: "nil pointer",
+		src:  (*int)(nil),
+		want: rdbmstypes.MakeParameterValueDbNull(),
+	}, {
+		name: "valid sql.NullInt32",
+		src:  sql.NullInt32{Int32: 5, Valid: true},
+		want: rdbmstypes.MakeParameterValueInt64(5),
+	}, {
+		name: "null sql.NullString",
+		src:  sql.NullString{},
+		want: rdbmstypes.MakeParameterValueDbNull(),
+	}, {
+		name: "already converted",
+		src:  rdbmstypes.MakeParameterValueBoolean(true),
+		want: rdbmstypes.MakeParameterValueBoolean(true),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toWasiParameterValue(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToWasiParameterValue_Time(t *testing.T) {
+	tests := []struct {
+		name string
+		src  time.Time
+		want string
+	}{{
+		name: "whole seconds",
+		src:  time.Date(2024, time.March, 15, 10, 30, 5, 0, time.UTC),
+		want: "2024-03-15 10:30:05",
+	}, {
+		name: "microseconds",
+		src:  time.Date(2024, time.March, 15, 10, 30, 5, 123456000, time.UTC),
+		want: "2024-03-15 10:30:05.123456",
+	}, {
+		name: "trailing zeros trimmed",
+		src:  time.Date(2024, time.March, 15, 10, 30, 5, 500000000, time.UTC),
+		want: "2024-03-15 10:30:05.5",
+	}, {
+		name: "nanoseconds truncated",
+		src:  time.Date(2024, time.March, 15, 10, 30, 5, 123456789, time.UTC),
+		want: "2024-03-15 10:30:05.123456",
+	}, {
+		name: "converted to UTC",
+		src:  time.Date(2024, time.March, 15, 10, 30, 5, 0, time.FixedZone("CET", 3600)),
+		want: "2024-03-15 09:30:05",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toWasiParameterValue(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, rdbmstypes.MakeParameterValueStr(tt.want), got)
+		})
+	}
+}
+
+func TestCheckNamedValue(t *testing.T) {
+	c := &conn{}
+
+	t.Run("converts", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 1, Value: int32(3)}
+		require.NoError(t, c.CheckNamedValue(nv))
+		assert.Equal(t, rdbmstypes.MakeParameterValueInt32(3), nv.Value)
+	})
+
+	for _, v := range []any{struct{}{}, []int{1}, map[string]int{}, complex(1, 2)} {
+		t.Run(fmt.Sprintf("rejects %T", v), func(t *testing.T) {
+			nv := &driver.NamedValue{Ordinal: 2, Name: "arg", Value: v}
+			err := c.CheckNamedValue(nv)
+
+			var convErr *ConversionError
+			require.True(t, errors.As(err, &convErr))
+			assert.Equal(t, 2, convErr.Ordinal)
+			assert.Equal(t, "arg", convErr.Name)
+			assert.Equal(t, v, convErr.Value)
+			assert.NoError(t, convErr.Err)
+		})
+	}
+
+	t.Run("valuer error", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 1, Value: brokenValuer{}}
+		err := c.CheckNamedValue(nv)
+
+		var convErr *ConversionError
+		require.True(t, errors.As(err, &convErr))
+		assert.EqualError(t, convErr.Err, "broken")
+	})
+}
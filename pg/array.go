@@ -0,0 +1,274 @@
+package pg
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	wittypes "go.bytecodealliance.org/pkg/wit/types"
+)
+
+// Array returns an adapter that lets a slice be used as a PostgreSQL array,
+// either as a query parameter or, when a is a pointer to a slice, as a scan
+// destination:
+//
+//	db.Exec("UPDATE pets SET tags = $1 WHERE id = $2", pg.Array(tags), id)
+//	row.Scan(pg.Array(&tags))
+//
+// The supported element types are int32, int64, string and Decimal. NULL
+// elements can be represented by a nil pointer element (as in []*string) or
+// by the sql.NullInt32, sql.NullInt64, sql.NullString and sql.Null[Decimal]
+// elements of the NullInt32Array, NullInt64Array, NullStringArray and
+// NullDecimalArray types. Scanning an array with a NULL element into a slice
+// that cannot represent it, such as []int32, returns an error.
+//
+// An array column without NULL elements can also be scanned directly into a
+// slice such as *[]int32. One with a NULL element must be scanned through
+// Array or a Null*Array type, so that NULLs are not silently lost.
+func Array(a any) interface {
+	driver.Valuer
+	sql.Scanner
+} {
+	return GenericArray{A: a}
+}
+
+// GenericArray implements [driver.Valuer] and [sql.Scanner] for the slice
+// types supported by Array.
+type GenericArray struct {
+	A any
+}
+
+// Value implements [driver.Valuer] so the array can be used as a query
+// parameter.
+func (a GenericArray) Value() (driver.Value, error) {
+	return toRdbmsParameterValue(a.A)
+}
+
+// Scan implements [sql.Scanner] so the array can be used as a scan
+// destination.
+func (a GenericArray) Scan(src any) error {
+	switch dest := a.A.(type) {
+	case *[]int32:
+		return scanArray(src, dest, notNull[int32])
+	case *[]*int32:
+		return scanArray(src, dest, nullable[int32])
+	case *[]sql.NullInt32:
+		return scanArray(src, dest, func(v *int32) (sql.NullInt32, error) {
+			if v == nil {
+				return sql.NullInt32{}, nil
+			}
+			return sql.NullInt32{Int32: *v, Valid: true}, nil
+		})
+	case *NullInt32Array:
+		return GenericArray{A: (*[]sql.NullInt32)(dest)}.Scan(src)
+	case *[]int64:
+		return scanArray(src, dest, notNull[int64])
+	case *[]*int64:
+		return scanArray(src, dest, nullable[int64])
+	case *[]sql.NullInt64:
+		return scanArray(src, dest, func(v *int64) (sql.NullInt64, error) {
+			if v == nil {
+				return sql.NullInt64{}, nil
+			}
+			return sql.NullInt64{Int64: *v, Valid: true}, nil
+		})
+	case *NullInt64Array:
+		return GenericArray{A: (*[]sql.NullInt64)(dest)}.Scan(src)
+	case *[]string:
+		return scanArray(src, dest, notNull[string])
+	case *[]*string:
+		return scanArray(src, dest, nullable[string])
+	case *[]sql.NullString:
+		return scanArray(src, dest, func(v *string) (sql.NullString, error) {
+			if v == nil {
+				return sql.NullString{}, nil
+			}
+			return sql.NullString{String: *v, Valid: true}, nil
+		})
+	case *NullStringArray:
+		return GenericArray{A: (*[]sql.NullString)(dest)}.Scan(src)
+	case *[]Decimal:
+		return scanArray(src, dest, func(v *string) (Decimal, error) {
+			s, err := notNull(v)
+			return Decimal(s), err
+		})
+	case *[]*Decimal:
+		return scanArray(src, dest, func(v *string) (*Decimal, error) {
+			if v == nil {
+				return nil, nil
+			}
+			d := Decimal(*v)
+			return &d, nil
+		})
+	case *[]sql.Null[Decimal]:
+		return scanArray(src, dest, func(v *string) (sql.Null[Decimal], error) {
+			if v == nil {
+				return sql.Null[Decimal]{}, nil
+			}
+			return sql.Null[Decimal]{V: Decimal(*v), Valid: true}, nil
+		})
+	case *NullDecimalArray:
+		return GenericArray{A: (*[]sql.Null[Decimal])(dest)}.Scan(src)
+	}
+	return fmt.Errorf("pg: cannot scan array into %T", a.A)
+}
+
+// NullInt32Array represents a PostgreSQL INT4[] value whose elements may be
+// NULL.
+type NullInt32Array []sql.NullInt32
+
+// Scan implements [sql.Scanner] so NullInt32Array can be used as a scan
+// destination.
+func (a *NullInt32Array) Scan(src any) error {
+	return Array((*[]sql.NullInt32)(a)).Scan(src)
+}
+
+// Value implements [driver.Valuer] so NullInt32Array can be used as a query
+// parameter.
+func (a NullInt32Array) Value() (driver.Value, error) {
+	return Array([]sql.NullInt32(a)).Value()
+}
+
+// NullInt64Array represents a PostgreSQL INT8[] value whose elements may be
+// NULL.
+type NullInt64Array []sql.NullInt64
+
+// Scan implements [sql.Scanner] so NullInt64Array can be used as a scan
+// destination.
+func (a *NullInt64Array) Scan(src any) error {
+	return Array((*[]sql.NullInt64)(a)).Scan(src)
+}
+
+// Value implements [driver.Valuer] so NullInt64Array can be used as a query
+// parameter.
+func (a NullInt64Array) Value() (driver.Value, error) {
+	return Array([]sql.NullInt64(a)).Value()
+}
+
+// NullStringArray represents a PostgreSQL TEXT[] value whose elements may be
+// NULL.
+type NullStringArray []sql.NullString
+
+// Scan implements [sql.Scanner] so NullStringArray can be used as a scan
+// destination.
+func (a *NullStringArray) Scan(src any) error {
+	return Array((*[]sql.NullString)(a)).Scan(src)
+}
+
+// Value implements [driver.Valuer] so NullStringArray can be used as a query
+// parameter.
+func (a NullStringArray) Value() (driver.Value, error) {
+	return Array([]sql.NullString(a)).Value()
+}
+
+// NullDecimalArray represents a PostgreSQL NUMERIC[] value whose elements may
+// be NULL.
+type NullDecimalArray []sql.Null[Decimal]
+
+// Scan implements [sql.Scanner] so NullDecimalArray can be used as a scan
+// destination.
+func (a *NullDecimalArray) Scan(src any) error {
+	return Array((*[]sql.Null[Decimal])(a)).Scan(src)
+}
+
+// Value implements [driver.Valuer] so NullDecimalArray can be used as a query
+// parameter.
+func (a NullDecimalArray) Value() (driver.Value, error) {
+	return Array([]sql.Null[Decimal](a)).Value()
+}
+
+// scanArray stores the array column value src in dest, converting each
+// element, given as nil for NULL, with conv. A NULL array sets dest to nil.
+func scanArray[T, E any](src any, dest *[]E, conv func(*T) (E, error)) error {
+	var elems []*T
+	switch src := src.(type) {
+	case nil:
+		*dest = nil
+		return nil
+	case []T:
+		elems = make([]*T, len(src))
+		for i := range src {
+			elems[i] = &src[i]
+		}
+	case []*T:
+		elems = src
+	default:
+		return fmt.Errorf("pg: cannot scan %T into %T", src, dest)
+	}
+
+	values := make([]E, len(elems))
+	for i, elem := range elems {
+		v, err := conv(elem)
+		if err != nil {
+			return fmt.Errorf("pg: cannot scan element %d into %T: %w", i, dest, err)
+		}
+		values[i] = v
+	}
+	*dest = values
+	return nil
+}
+
+func notNull[T any](v *T) (T, error) {
+	if v == nil {
+		var zero T
+		return zero, fmt.Errorf("NULL element in array of %T", zero)
+	}
+	return *v, nil
+}
+
+func nullable[T any](v *T) (*T, error) {
+	if v == nil {
+		return nil, nil
+	}
+	value := *v
+	return &value, nil
+}
+
+// fromArray converts an array received from the host. Arrays without NULL
+// elements are returned as []T, the scan type of their column, so they can be
+// scanned directly into a *[]T. Arrays with a NULL element are returned as
+// []*T, with nil for each NULL element, which only Array and the Null*Array
+// types accept.
+func fromArray[T any](v []wittypes.Option[T]) any {
+	values := make([]T, len(v))
+	for i, x := range v {
+		if x.IsNone() {
+			return fromNullableArray(v)
+		}
+		values[i] = x.Some()
+	}
+	return values
+}
+
+func fromNullableArray[T any](v []wittypes.Option[T]) []*T {
+	values := make([]*T, len(v))
+	for i, x := range v {
+		if x.IsSome() {
+			value := x.Some()
+			values[i] = &value
+		}
+	}
+	return values
+}
+
+// toNullableOptionSlice converts the elements of v with f, which reports
+// whether the element is non-NULL.
+func toNullableOptionSlice[E, T any](v []E, f func(E) (T, bool)) []wittypes.Option[T] {
+	values := make([]wittypes.Option[T], len(v))
+	for i, x := range v {
+		if value, ok := f(x); ok {
+			values[i] = wittypes.Some(value)
+		} else {
+			values[i] = wittypes.None[T]()
+		}
+	}
+	return values
+}
+
+func fromPtr[T any](v *T) (T, bool) {
+	if v == nil {
+		var zero T
+		return zero, false
+	}
+	return *v, true
+}
@@ -0,0 +1,72 @@
+package pg
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/spinframework/spin-go-sdk/v3/variables"
+)
+
+// CARootFromVariable returns the PEM-encoded CA certificate held by the Spin
+// application variable name, for use as Options.CARootPEM. It returns an error
+// if the variable cannot be read or does not hold a valid certificate.
+func CARootFromVariable(name string) (string, error) {
+	value, err := variables.Get(name)
+	if err != nil {
+		return "", fmt.Errorf("pg: CA root variable %q: %w", name, err)
+	}
+	if err := checkCARoot([]byte(value)); err != nil {
+		return "", fmt.Errorf("pg: CA root variable %q: %w", name, err)
+	}
+	return value, nil
+}
+
+// CARootFromFile returns the PEM-encoded CA certificate in the file name of
+// fsys, for use as Options.CARootPEM. fsys may be an embed.FS bundled into
+// the component or, for files mounted into the component, os.DirFS. It
+// returns an error if the file cannot be read or does not hold a valid
+// certificate.
+func CARootFromFile(fsys fs.FS, name string) (string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", fmt.Errorf("pg: CA root file %q: %w", name, err)
+	}
+	if err := checkCARoot(data); err != nil {
+		return "", fmt.Errorf("pg: CA root file %q: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// checkCARoot reports whether data consists only of PEM-encoded X.509
+// certificates, so that a bad certificate is reported before it reaches the
+// host.
+func checkCARoot(data []byte) error {
+	rest := bytes.TrimSpace(data)
+	if len(rest) == 0 {
+		return errors.New("no PEM data")
+	}
+
+	for n := 1; len(rest) > 0; n++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			if n == 1 {
+				return errors.New("no PEM certificate found")
+			}
+			return fmt.Errorf("unexpected data after PEM block %d", n-1)
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("PEM block %d has type %q, want \"CERTIFICATE\"", n, block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("PEM block %d: %w", n, err)
+		}
+		rest = bytes.TrimSpace(rest)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package pg
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/fs"
+	"math/big"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCARoot(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCheckCARoot(t *testing.T) {
+	root := testCARoot(t)
+
+	tests := []struct {
+		name    string
+		pem     string
+		wantErr string
+	}{{
+		name: "certificate",
+		pem:  root,
+	}, {
+		name: "bundle",
+		pem:  root + "\n" + root,
+	}, {
+		name:    "empty",
+		pem:     " \n",
+		wantErr: "no PEM data",
+	}, {
+		name:    "not PEM",
+		pem:     "not a certificate",
+		wantErr: "no PEM certificate found",
+	}, {
+		name:    "trailing data",
+		pem:     root + "garbage",
+		wantErr: "unexpected data after PEM block 1",
+	}, {
+		name:    "private key",
+		pem:     string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}})),
+		wantErr: `PEM block 1 has type "PRIVATE KEY", want "CERTIFICATE"`,
+	}, {
+		name:    "corrupt certificate",
+		pem:     root + string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1, 2, 3}})),
+		wantErr: "PEM block 2: x509: malformed certificate",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCARoot([]byte(tt.pem))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCARootFromFile(t *testing.T) {
+	root := testCARoot(t)
+	fsys := fstest.MapFS{
+		"certs/ca.pem":  {Data: []byte(root)},
+		"certs/bad.pem": {Data: []byte("bad")},
+	}
+
+	got, err := CARootFromFile(fsys, "certs/ca.pem")
+	require.NoError(t, err)
+	assert.Equal(t, root, got)
+
+	_, err = CARootFromFile(fsys, "certs/bad.pem")
+	assert.EqualError(t, err, `pg: CA root file "certs/bad.pem": no PEM certificate found`)
+
+	_, err = CARootFromFile(fsys, "certs/missing.pem")
+	assert.EqualError(t, err, `pg: CA root file "certs/missing.pem": open certs/missing.pem: file does not exist`)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestNewConnector(t *testing.T) {
+	c, err := NewConnector("host=db", Options{CARootPEM: testCARoot(t)})
+	require.NoError(t, err)
+	assert.Equal(t, "host=db", c.address)
+
+	_, err = NewConnector("host=db", Options{CARootPEM: "bad"})
+	assert.EqualError(t, err, "pg: invalid CA root: no PEM certificate found")
+}
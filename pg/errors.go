@@ -0,0 +1,88 @@
+package pg
+
+import "errors"
+
+//go:generate go run ./internal/gensqlstate -pg 17 -o sqlstate.go
+
+// IsUniqueViolation reports whether err is a *QueryDBError for a unique
+// constraint violation.
+func IsUniqueViolation(err error) bool {
+	return hasCode(err, CodeUniqueViolation)
+}
+
+// IsForeignKeyViolation reports whether err is a *QueryDBError for a foreign
+// key constraint violation.
+func IsForeignKeyViolation(err error) bool {
+	return hasCode(err, CodeForeignKeyViolation)
+}
+
+// IsNotNullViolation reports whether err is a *QueryDBError for a NOT NULL
+// constraint violation.
+func IsNotNullViolation(err error) bool {
+	return hasCode(err, CodeNotNullViolation)
+}
+
+// IsCheckViolation reports whether err is a *QueryDBError for a check
+// constraint violation.
+func IsCheckViolation(err error) bool {
+	return hasCode(err, CodeCheckViolation)
+}
+
+// IsSerializationFailure reports whether err is a *QueryDBError for a
+// serialization failure. The transaction can be retried.
+func IsSerializationFailure(err error) bool {
+	return hasCode(err, CodeSerializationFailure)
+}
+
+// IsDeadlock reports whether err is a *QueryDBError for a detected deadlock.
+// The transaction can be retried.
+func IsDeadlock(err error) bool {
+	return hasCode(err, CodeDeadlockDetected)
+}
+
+func hasCode(err error, code string) bool {
+	var dbErr *QueryDBError
+	return errors.As(err, &dbErr) && dbErr.Code == code
+}
+
+// Class returns the class of the error's SQLSTATE code, which is its first two
+// characters, such as "23" for integrity constraint violations.
+func (e *QueryDBError) Class() string {
+	if len(e.Code) < 2 {
+		return e.Code
+	}
+	return e.Code[:2]
+}
+
+// Extra returns the value of the named field in Extras, or "" if Postgres did
+// not provide it.
+func (e *QueryDBError) Extra(name string) string {
+	for _, extra := range e.Extras {
+		if extra[0] == name {
+			return extra[1]
+		}
+	}
+	return ""
+}
+
+// Schema returns the name of the schema containing the object associated with
+// the error, if any.
+func (e *QueryDBError) Schema() string {
+	return e.Extra("schema")
+}
+
+// Table returns the name of the table associated with the error, if any.
+func (e *QueryDBError) Table() string {
+	return e.Extra("table")
+}
+
+// Column returns the name of the column associated with the error, if any.
+func (e *QueryDBError) Column() string {
+	return e.Extra("column")
+}
+
+// Constraint returns the name of the constraint associated with the error, if
+// any.
+func (e *QueryDBError) Constraint() string {
+	return e.Extra("constraint")
+}
@@ -0,0 +1,91 @@
+package pg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		is   func(error) bool
+		want bool
+	}{{
+		name: "unique violation",
+		err:  &QueryDBError{Code: "23505"},
+		is:   IsUniqueViolation,
+		want: true,
+	}, {
+		name: "wrapped unique violation",
+		err:  fmt.Errorf("insert user: %w", &QueryDBError{Code: CodeUniqueViolation}),
+		is:   IsUniqueViolation,
+		want: true,
+	}, {
+		name: "foreign key violation",
+		err:  &QueryDBError{Code: "23503"},
+		is:   IsForeignKeyViolation,
+		want: true,
+	}, {
+		name: "not null violation",
+		err:  &QueryDBError{Code: "23502"},
+		is:   IsNotNullViolation,
+		want: true,
+	}, {
+		name: "check violation",
+		err:  &QueryDBError{Code: "23514"},
+		is:   IsCheckViolation,
+		want: true,
+	}, {
+		name: "serialization failure",
+		err:  &QueryDBError{Code: "40001"},
+		is:   IsSerializationFailure,
+		want: true,
+	}, {
+		name: "deadlock",
+		err:  &QueryDBError{Code: "40P01"},
+		is:   IsDeadlock,
+		want: true,
+	}, {
+		name: "other code",
+		err:  &QueryDBError{Code: CodeForeignKeyViolation},
+		is:   IsUniqueViolation,
+		want: false,
+	}, {
+		name: "text error",
+		err:  errors.New("23505"),
+		is:   IsUniqueViolation,
+		want: false,
+	}, {
+		name: "nil",
+		err:  nil,
+		is:   IsDeadlock,
+		want: false,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.is(tt.err))
+		})
+	}
+}
+
+func TestQueryDBError_Extras(t *testing.T) {
+	err := &QueryDBError{
+		Code: CodeForeignKeyViolation,
+		Extras: [][2]string{
+			{"schema", "public"},
+			{"table", "orders"},
+			{"constraint", "orders_user_id_fkey"},
+		},
+	}
+
+	assert.Equal(t, "23", err.Class())
+	assert.Equal(t, "public", err.Schema())
+	assert.Equal(t, "orders", err.Table())
+	assert.Equal(t, "orders_user_id_fkey", err.Constraint())
+	assert.Equal(t, "", err.Column())
+}
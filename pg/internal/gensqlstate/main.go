@@ -0,0 +1,179 @@
+// Command gensqlstate generates the SQLSTATE constants of package pg from
+// src/backend/utils/errcodes.txt of the PostgreSQL sources:
+//
+//	go run ./internal/gensqlstate -pg 17 -o sqlstate.go
+//
+// The file is downloaded from the REL_<pg>_STABLE branch, unless -in names a
+// local copy.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// initialisms are the words of condition names that are written in capitals,
+// or in the case PostgreSQL uses, in Go identifiers.
+var initialisms = map[string]string{
+	"fdw":      "FDW",
+	"io":       "IO",
+	"json":     "JSON",
+	"plpgsql":  "PLpgSQL",
+	"sql":      "SQL",
+	"sqlstate": "SQLState",
+	"srf":      "SRF",
+	"xml":      "XML",
+}
+
+// names overrides the identifiers of conditions whose names are shared with
+// another condition.
+var names = map[string]string{
+	"01004": "StringDataRightTruncationWarning",
+	"38002": "ModifyingSQLDataNotPermittedExternal",
+	"38003": "ProhibitedSQLStatementAttemptedExternal",
+	"38004": "ReadingSQLDataNotPermittedExternal",
+}
+
+type section struct {
+	Title string
+	Codes []code
+}
+
+type code struct {
+	Name, Code string
+}
+
+func main() {
+	version := flag.String("pg", "17", "major `version` of PostgreSQL to read errcodes.txt from")
+	in := flag.String("in", "", "read errcodes.txt from `file` instead of downloading it")
+	out := flag.String("o", "sqlstate.go", "write the constants to `file`")
+	flag.Parse()
+
+	var r io.Reader
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		r = f
+	} else {
+		url := "https://raw.githubusercontent.com/postgres/postgres/REL_" + *version + "_STABLE/src/backend/utils/errcodes.txt"
+		resp, err := http.Get(url)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("%s: %s", url, resp.Status)
+		}
+		r = resp.Body
+	}
+
+	sections, err := parse(r)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(*version, sections)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parse reads the sections of errcodes.txt. Each line of a section is
+//
+//	sqlstate    E/W/S    errcode_macro_name    [spec_name]
+func parse(r io.Reader) ([]section, error) {
+	var sections []section
+	seen := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if title, ok := strings.CutPrefix(line, "Section: "); ok {
+			sections = append(sections, section{Title: title})
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(fields) > 4 || len(sections) == 0 {
+			return nil, fmt.Errorf("line %d: unexpected %q", n, line)
+		}
+		spec := strings.ToLower(strings.TrimPrefix(fields[2], "ERRCODE_"))
+		if len(fields) == 4 {
+			spec = fields[3]
+		}
+		name := names[fields[0]]
+		if name == "" {
+			name = identifier(spec)
+		}
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("line %d: %s and %s are both named %s; add one to names", n, other, fields[0], name)
+		}
+		seen[name] = fields[0]
+
+		s := &sections[len(sections)-1]
+		s.Codes = append(s.Codes, code{Name: "Code" + name, Code: fields[0]})
+	}
+	return sections, scanner.Err()
+}
+
+// identifier converts a condition name such as invalid_sql_statement_name to
+// a Go identifier such as InvalidSQLStatementName.
+func identifier(spec string) string {
+	var b strings.Builder
+	for word := range strings.SplitSeq(spec, "_") {
+		if s, ok := initialisms[word]; ok {
+			b.WriteString(s)
+		} else if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+var tmpl = template.Must(template.New("").Parse(`// Code generated by gensqlstate from errcodes.txt of PostgreSQL {{.Version}}. DO NOT EDIT.
+
+package pg
+
+// SQLSTATE codes reported in QueryDBError.Code. The table is generated from
+// src/backend/utils/errcodes.txt of PostgreSQL {{.Version}}, keeping its class
+// sections and order, with each condition name converted to a Go identifier.
+// The first two characters of a code identify its class; see
+// QueryDBError.Class.
+const (
+{{- range $i, $s := .Sections}}
+{{if $i}}
+{{end}}	// {{$s.Title}}
+{{- range $s.Codes}}
+	{{.Name}} = "{{.Code}}"
+{{- end}}
+{{- end}}
+)
+`))
+
+func generate(version string, sections []section) ([]byte, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Version  string
+		Sections []section
+	}{version, sections})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
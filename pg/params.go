@@ -0,0 +1,118 @@
+package pg
+
+import (
+	"strconv"
+	"strings"
+
+	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
+)
+
+// rewriteParams rewrites the "@name" parameters of a PostgreSQL statement to
+// the positional "$n" form understood by the server. It returns the rewritten
+// query and the name of each parameter in position order, with "" for the
+// "$n" parameters already in the query.
+//
+// Named parameters are numbered after the largest "$n" in the query, and a
+// name used more than once refers to the same parameter. An "@" that follows
+// an operator character, such as in the "<@" and "@>" operators, is not a
+// parameter. String literals, quoted identifiers and comments are skipped.
+func rewriteParams(query string) (string, []string) {
+	var params []string
+	scanParams(query, func(i, j int) {
+		if query[i] != '$' {
+			return
+		}
+		n, err := strconv.Atoi(query[i+1 : j])
+		if err != nil || n <= 0 {
+			return
+		}
+		for len(params) < n {
+			params = append(params, "")
+		}
+	})
+
+	index := map[string]int{}
+	var b strings.Builder
+	last := 0
+	scanParams(query, func(i, j int) {
+		if query[i] != '@' {
+			return
+		}
+		name := query[i+1 : j]
+		n, ok := index[name]
+		if !ok {
+			params = append(params, name)
+			n = len(params)
+			index[name] = n
+		}
+		b.WriteString(query[last:i])
+		b.WriteString("$")
+		b.WriteString(strconv.Itoa(n))
+		last = j
+	})
+
+	if last == 0 {
+		return query, params
+	}
+	b.WriteString(query[last:])
+	return b.String(), params
+}
+
+// scanParams calls fn with the bounds of each "$n" and "@name" parameter
+// in query.
+func scanParams(query string, fn func(i, j int)) {
+	for i := 0; i < len(query); {
+		if j := spindb.Postgres.Skip(query, i); j > i {
+			i = j
+			continue
+		}
+		switch c := query[i]; c {
+		case '$':
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+			if j > i+1 {
+				fn(i, j)
+			}
+			i = j
+		case '@':
+			j := i + 1
+			if j < len(query) && isIdentStart(query[j]) && (i == 0 || !isOperatorChar(query[i-1])) {
+				for j < len(query) && isIdentChar(query[j]) {
+					j++
+				}
+				fn(i, j)
+				i = j
+				continue
+			}
+			i++
+		default:
+			if isIdentStart(c) {
+				// Identifiers may contain "$", which does not start a
+				// parameter or a dollar-quoted string there.
+				for i < len(query) && (isIdentChar(query[i]) || query[i] == '$') {
+					i++
+				}
+			} else {
+				i++
+			}
+		}
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isOperatorChar(c byte) bool {
+	return strings.IndexByte("+-*/<>=~!@#%^&|`?", c) >= 0
+}
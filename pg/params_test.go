@@ -0,0 +1,69 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantQuery  string
+		wantParams []string
+	}{{
+		name:       "none",
+		query:      "SELECT 1",
+		wantQuery:  "SELECT 1",
+		wantParams: nil,
+	}, {
+		name:       "positional",
+		query:      "SELECT * FROM t WHERE a = $1 AND b = $2",
+		wantQuery:  "SELECT * FROM t WHERE a = $1 AND b = $2",
+		wantParams: []string{"", ""},
+	}, {
+		name:       "named",
+		query:      "INSERT INTO t (id, name) VALUES (@id, @name)",
+		wantQuery:  "INSERT INTO t (id, name) VALUES ($1, $2)",
+		wantParams: []string{"id", "name"},
+	}, {
+		name:       "repeated name",
+		query:      "SELECT * FROM t WHERE a = @id OR b = @id",
+		wantQuery:  "SELECT * FROM t WHERE a = $1 OR b = $1",
+		wantParams: []string{"id"},
+	}, {
+		name:       "mixed",
+		query:      "SELECT $2, @id, $1",
+		wantQuery:  "SELECT $2, $3, $1",
+		wantParams: []string{"", "", "id"},
+	}, {
+		name:       "operators",
+		query:      "SELECT * FROM t WHERE tags @> @tags AND ids <@ @ids AND @ -1 = 1",
+		wantQuery:  "SELECT * FROM t WHERE tags @> $1 AND ids <@ $2 AND @ -1 = 1",
+		wantParams: []string{"tags", "ids"},
+	}, {
+		name:       "quoted",
+		query:      `SELECT '@a', "@b", E'\'@c', 'it''s @d', $$ @e $1 $$, $tag$ @f $tag$ FROM t WHERE x = @x`,
+		wantQuery:  `SELECT '@a', "@b", E'\'@c', 'it''s @d', $$ @e $1 $$, $tag$ @f $tag$ FROM t WHERE x = $1`,
+		wantParams: []string{"x"},
+	}, {
+		name:       "comments",
+		query:      "SELECT @a -- @b $2\n, /* @c /* $3 */ @d */ $1",
+		wantQuery:  "SELECT $2 -- @b $2\n, /* @c /* $3 */ @d */ $1",
+		wantParams: []string{"", "a"},
+	}, {
+		name:       "dollar in identifier",
+		query:      "SELECT a$1 FROM t WHERE b = @b",
+		wantQuery:  "SELECT a$1 FROM t WHERE b = $1",
+		wantParams: []string{"b"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, params := rewriteParams(tt.query)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
@@ -1,14 +1,32 @@
 // Package pg provides a database/sql driver for PostgreSQL databases within Spin components.
+//
+// Besides the positional "$n" parameters, statements may use "@name"
+// parameters bound from sql.Named arguments. They are rewritten to "$n"
+// before the statement is sent to the host:
+//
+//	db.Exec("UPDATE pets SET prey = @prey WHERE id = @id",
+//		sql.Named("id", 4), sql.Named("prey", "bananas"))
+//
+// To trust a private CA when connecting over TLS, pass its certificate in
+// Options:
+//
+//	ca, err := pg.CARootFromVariable("db_ca_root")
+//	// if err != nil { ... }
+//
+//	db, err := pg.OpenWithOptions(address, pg.Options{CARootPEM: ca})
+//	// if err != nil { ... }
 package pg
 
 import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding"
 	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"time"
 
 	pg "github.com/spinframework/spin-go-sdk/v3/imports/spin_postgres_4_2_0_postgres"
@@ -18,31 +36,77 @@ import (
 
 // Open returns a new connection to the database.
 func Open(name string) *sql.DB {
-	return sql.OpenDB(&connector{name: name})
+	return sql.OpenDB(&Connector{address: name})
+}
+
+// Options configures the connections made by a Connector.
+type Options struct {
+	// CARootPEM is a PEM-encoded CA certificate to trust when verifying the
+	// server's TLS certificate, in addition to the host's default roots. See
+	// CARootFromVariable and CARootFromFile for loading it.
+	CARootPEM string
+}
+
+// OpenWithOptions returns a new connection to the database at address,
+// configured by opts. It returns an error if the options are invalid.
+func OpenWithOptions(address string, opts Options) (*sql.DB, error) {
+	c, err := NewConnector(address, opts)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(c), nil
 }
 
-// connector implements driver.Connector.
-type connector struct {
-	conn *conn
-	name string
+// Connector implements driver.Connector, for use with sql.OpenDB.
+type Connector struct {
+	conn    *conn
+	address string
+	opts    Options
+}
+
+var _ driver.Connector = (*Connector)(nil)
+
+// NewConnector returns a Connector for the database at address, configured by
+// opts. It returns an error if opts.CARootPEM is set but is not a valid PEM
+// certificate.
+func NewConnector(address string, opts Options) (*Connector, error) {
+	if opts.CARootPEM != "" {
+		if err := checkCARoot([]byte(opts.CARootPEM)); err != nil {
+			return nil, fmt.Errorf("pg: invalid CA root: %w", err)
+		}
+	}
+	return &Connector{address: address, opts: opts}, nil
 }
 
 // Connect returns a connection to the database.
-func (d *connector) Connect(_ context.Context) (driver.Conn, error) {
+func (d *Connector) Connect(ctx context.Context) (driver.Conn, error) {
 	if d.conn != nil {
 		return d.conn, nil
 	}
-	return d.Open(d.name)
+	return d.open(ctx, d.address)
 }
 
 // Driver returns the underlying Driver of the Connector.
-func (d *connector) Driver() driver.Driver {
+func (d *Connector) Driver() driver.Driver {
 	return d
 }
 
 // Open returns a new connection to the database.
-func (d *connector) Open(name string) (driver.Conn, error) {
-	results := pg.ConnectionOpenAsync(name)
+func (d *Connector) Open(name string) (driver.Conn, error) {
+	return d.open(context.Background(), name)
+}
+
+func (d *Connector) open(ctx context.Context, address string) (driver.Conn, error) {
+	results, err := spindb.Await(ctx, func() wittypes.Result[*pg.Connection, pg.Error] {
+		return d.connect(address)
+	}, func(results wittypes.Result[*pg.Connection, pg.Error]) {
+		if results.IsOk() {
+			results.Ok().Drop()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
 	if results.IsErr() {
 		return nil, toError(results.Err())
 	}
@@ -50,16 +114,45 @@ func (d *connector) Open(name string) (driver.Conn, error) {
 	return d.conn, nil
 }
 
+// connect opens a host connection, going through a connection builder when
+// there are options to apply.
+func (d *Connector) connect(address string) wittypes.Result[*pg.Connection, pg.Error] {
+	if d.opts.CARootPEM == "" {
+		return pg.ConnectionOpenAsync(address)
+	}
+
+	builder := pg.MakeConnectionBuilder(address)
+	defer builder.Drop()
+
+	if result := builder.SetCaRoot(d.opts.CARootPEM); result.IsErr() {
+		return wittypes.Err[*pg.Connection](result.Err())
+	}
+	return builder.BuildAsync()
+}
+
+// Close closes the connection to the database.
+func (d *Connector) Close() error {
+	if d.conn != nil {
+		d.conn.Close()
+	}
+
+	return nil
+}
+
 // conn implements driver.Conn
 type conn struct {
 	spinConn pg.Connection
 }
 
 var _ driver.Conn = (*conn)(nil)
+var _ driver.NamedValueChecker = (*conn)(nil)
+var _ driver.QueryerContext = (*conn)(nil)
+var _ driver.ExecerContext = (*conn)(nil)
 
 // Prepare returns a prepared statement, bound to this connection.
 func (c *conn) Prepare(query string) (driver.Stmt, error) {
-	return &stmt{conn: c, query: query}, nil
+	query, params := rewriteParams(query)
+	return &stmt{conn: c, query: query, params: params}, nil
 }
 
 func (c *conn) Close() error {
@@ -71,33 +164,39 @@ func (c *conn) Begin() (driver.Tx, error) {
 	return nil, errors.New("transactions are unsupported by this driver")
 }
 
-type stmt struct {
-	conn  *conn
-	query string
+// QueryContext executes a query that may return rows, such as a SELECT.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	query, params := rewriteParams(query)
+	return c.query(ctx, query, params, args)
 }
 
-var _ driver.Stmt = (*stmt)(nil)
-var _ driver.ColumnConverter = (*stmt)(nil)
-
-// Close closes the statement.
-func (s *stmt) Close() error {
-	return nil
-}
-
-// NumInput returns the number of placeholder parameters.
-func (s *stmt) NumInput() int {
-	// Golang sql won't sanity check argument counts before Query.
-	return -1
+// ExecContext executes a query that doesn't return rows, such as an INSERT or
+// UPDATE.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	query, params := rewriteParams(query)
+	return c.exec(ctx, query, params, args)
 }
 
-// Query executes a query that may return rows, such as a SELECT.
-func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
-	rdbmsParams := make([]pg.ParameterValue, len(args))
-	for i, v := range args {
-		rdbmsParams[i] = toRdbmsParameterValue(v)
+// query binds args to the parameters of query, which are described by params,
+// and runs it. Binding errors are returned before calling the host.
+func (c *conn) query(ctx context.Context, query string, params []string, args []driver.NamedValue) (driver.Rows, error) {
+	rdbmsParams, err := bindParameterValues(params, args)
+	if err != nil {
+		return nil, err
 	}
 
-	results := s.conn.spinConn.QueryAsync(s.query, rdbmsParams)
+	type queryResult = wittypes.Result[wittypes.Tuple3[[]pg.Column, *wittypes.StreamReader[[]pg.DbValue], *wittypes.FutureReader[wittypes.Result[wittypes.Unit, pg.Error]]], pg.Error]
+	results, err := spindb.Await(ctx, func() queryResult {
+		return c.spinConn.QueryAsync(query, rdbmsParams)
+	}, func(results queryResult) {
+		if results.IsOk() {
+			results.Ok().F1.Drop()
+			results.Ok().F2.Drop()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
 	if results.IsErr() {
 		return nil, toError(results.Err())
 	}
@@ -105,13 +204,14 @@ func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
 	tuple := results.Ok()
 	cols := tuple.F0
 	colNames := make([]string, len(cols))
-	colTypes := make([]uint8, len(cols))
+	colTypes := make([]pg.DbDataType, len(cols))
 	for i, c := range cols {
 		colNames[i] = c.Name
-		colTypes[i] = c.DataType.Tag()
+		colTypes[i] = c.DataType
 	}
 
 	rows := &rows{
+		ctx:        ctx,
 		columns:    colNames,
 		columnType: colTypes,
 		stream:     tuple.F1,
@@ -122,25 +222,79 @@ func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
 	return rows, nil
 }
 
-// Exec executes a query that doesn't return rows, such as an INSERT or
-// UPDATE.
-func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
-	rdbmsParams := make([]pg.ParameterValue, len(args))
-	for i, v := range args {
-		rdbmsParams[i] = toRdbmsParameterValue(v)
+func (c *conn) exec(ctx context.Context, query string, params []string, args []driver.NamedValue) (driver.Result, error) {
+	rdbmsParams, err := bindParameterValues(params, args)
+	if err != nil {
+		return nil, err
 	}
 
-	queryResult := s.conn.spinConn.ExecuteAsync(s.query, rdbmsParams)
+	queryResult, err := spindb.Await(ctx, func() wittypes.Result[uint64, pg.Error] {
+		return c.spinConn.ExecuteAsync(query, rdbmsParams)
+	}, func(wittypes.Result[uint64, pg.Error]) {
+		// The result holds no handles, and the statement runs to completion
+		// on the host.
+	})
+	if err != nil {
+		return nil, err
+	}
 	if queryResult.IsErr() {
 		return &result{}, toError(queryResult.Err())
 	}
 
-	return &result{}, nil
+	return &result{rowsAffected: int64(queryResult.Ok())}, nil
+}
+
+// CheckNamedValue converts a query argument to a Postgres parameter value,
+// returning a *ConversionError if its type is not supported.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return spindb.CheckNamedValue(nv, toRdbmsParameterValue)
+}
+
+// ConversionError is returned when a query argument cannot be converted to a
+// Postgres parameter value.
+type ConversionError = spindb.ConversionError
+
+type stmt struct {
+	conn   *conn
+	query  string
+	params []string
+}
+
+var _ driver.Stmt = (*stmt)(nil)
+var _ driver.StmtQueryContext = (*stmt)(nil)
+var _ driver.StmtExecContext = (*stmt)(nil)
+
+// Close closes the statement.
+func (s *stmt) Close() error {
+	return nil
 }
 
-// ColumnConverter returns GlobalParameterConverter to prevent using driver.DefaultParameterConverter.
-func (s *stmt) ColumnConverter(_ int) driver.ValueConverter {
-	return spindb.GlobalParameterConverter
+// NumInput returns the number of placeholder parameters.
+func (s *stmt) NumInput() int {
+	return len(s.params)
+}
+
+// Query executes a query that may return rows, such as a SELECT.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.query(context.Background(), s.query, s.params, spindb.ToNamedValues(args))
+}
+
+// Exec executes a query that doesn't return rows, such as an INSERT or
+// UPDATE.
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.exec(context.Background(), s.query, s.params, spindb.ToNamedValues(args))
+}
+
+// QueryContext executes a query that may return rows, such as a SELECT.
+// If ctx is done before the query completes, the host row stream is dropped.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.query(ctx, s.query, s.params, args)
+}
+
+// ExecContext executes a query that doesn't return rows, such as an INSERT or
+// UPDATE.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.exec(ctx, s.query, s.params, args)
 }
 
 type result struct {
@@ -156,8 +310,9 @@ func (r result) RowsAffected() (int64, error) {
 }
 
 type rows struct {
+	ctx        context.Context
 	columns    []string
-	columnType []uint8
+	columnType []pg.DbDataType
 	next       []any
 	stream     *wittypes.StreamReader[[]pg.DbValue]
 	future     *wittypes.FutureReader[wittypes.Result[wittypes.Unit, pg.Error]]
@@ -166,6 +321,9 @@ type rows struct {
 
 var _ driver.Rows = (*rows)(nil)
 var _ driver.RowsColumnTypeScanType = (*rows)(nil)
+var _ driver.RowsColumnTypeDatabaseTypeName = (*rows)(nil)
+var _ driver.RowsColumnTypeNullable = (*rows)(nil)
+var _ driver.RowsColumnTypePrecisionScale = (*rows)(nil)
 var _ driver.RowsNextResultSet = (*rows)(nil)
 
 // Columns returns the column names.
@@ -175,8 +333,10 @@ func (r *rows) Columns() []string {
 
 // Close closes the rows iterator.
 func (r *rows) Close() error {
-	r.stream.Drop()
-	r.future.Drop()
+	if r.stream != nil {
+		r.stream.Drop()
+		r.future.Drop()
+	}
 	r.stream = nil
 	r.future = nil
 	r.next = nil
@@ -184,18 +344,38 @@ func (r *rows) Close() error {
 	return nil
 }
 
+// pull reads the next row. At the end of the stream it returns nil and records
+// the outcome of the query. If the context is done first, the context's error
+// is recorded instead, and the stream and future are dropped once the pending
+// read completes rather than by Close.
 func (r *rows) pull() []any {
-	buffer := [][]pg.DbValue{nil}
-	if r.stream.Read(buffer) == 1 {
-		return toRow(buffer[0])
+	stream, future := r.stream, r.future
+	next, err := spindb.Await(r.ctx, func() spindb.Row {
+		buffer := [][]pg.DbValue{nil}
+		if stream.Read(buffer) == 1 {
+			return spindb.Row{Values: toRow(buffer[0])}
+		}
+		result := future.Read()
+		if result.IsOk() {
+			return spindb.Row{Err: io.EOF}
+		}
+		return spindb.Row{Err: toError(result.Err())}
+	}, func(spindb.Row) {
+		stream.Drop()
+		future.Drop()
+	})
+	if err != nil {
+		// The pending read owns the stream and future now, and release
+		// drops them once it completes.
+		r.stream = nil
+		r.future = nil
+		r.result = err
+		return nil
 	}
-	result := r.future.Read()
-	if result.IsOk() {
-		r.result = io.EOF
-	} else {
-		r.result = toError(result.Err())
+	if next.Values == nil {
+		r.result = next.Err
 	}
-	return nil
+	return next.Values
 }
 
 // Next moves the cursor to the next row.
@@ -231,43 +411,85 @@ func (r *rows) NextResultSet() error {
 
 // ColumnTypeScanType returns the value type that can be used to scan types into.
 func (r *rows) ColumnTypeScanType(index int) reflect.Type {
-	return colTypeToReflectType(r.columnType[index])
+	return colTypeToReflectType(r.columnType[index].Tag())
+}
+
+// ColumnTypeDatabaseTypeName returns the uppercase PostgreSQL type name of
+// the column, such as "INT4", "TIMESTAMP" or "_TEXT" for a text array. Types
+// the host does not map are reported by the name it gives them.
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return colTypeToDatabaseTypeName(r.columnType[index])
+}
+
+// ColumnTypeNullable reports whether the column may be NULL. The host does
+// not report column nullability, so ok is always false.
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return false, false
+}
+
+// ColumnTypePrecisionScale returns the precision and scale of a decimal
+// column. The host does not report type modifiers such as NUMERIC(10, 2), so
+// ok is always false.
+func (r *rows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	return 0, 0, false
+}
+
+func bindParameterValues(params []string, args []driver.NamedValue) ([]pg.ParameterValue, error) {
+	values, err := spindb.BindArgs(params, args)
+	if err != nil {
+		return nil, err
+	}
+	return toRdbmsParameterValues(values)
+}
+
+func toRdbmsParameterValues(args []driver.Value) ([]pg.ParameterValue, error) {
+	rdbmsParams := make([]pg.ParameterValue, len(args))
+	for i, v := range args {
+		p, err := toRdbmsParameterValue(v)
+		if err != nil {
+			return nil, err
+		}
+		rdbmsParams[i] = p
+	}
+	return rdbmsParams, nil
 }
 
-func toRdbmsParameterValue(x any) pg.ParameterValue {
+func toRdbmsParameterValue(x any) (pg.ParameterValue, error) {
 	switch v := x.(type) {
+	case pg.ParameterValue:
+		return v, nil
 	case bool:
-		return pg.MakeParameterValueBoolean(v)
+		return pg.MakeParameterValueBoolean(v), nil
 	case int8:
-		return pg.MakeParameterValueInt8(v)
+		return pg.MakeParameterValueInt8(v), nil
 	case int16:
-		return pg.MakeParameterValueInt16(v)
+		return pg.MakeParameterValueInt16(v), nil
 	case int32:
-		return pg.MakeParameterValueInt32(v)
+		return pg.MakeParameterValueInt32(v), nil
 	case int64:
-		return pg.MakeParameterValueInt64(v)
+		return pg.MakeParameterValueInt64(v), nil
 	case int:
-		return pg.MakeParameterValueInt64(int64(v))
+		return pg.MakeParameterValueInt64(int64(v)), nil
 	case float32:
-		return pg.MakeParameterValueFloating32(v)
+		return pg.MakeParameterValueFloating32(v), nil
 	case float64:
-		return pg.MakeParameterValueFloating64(v)
+		return pg.MakeParameterValueFloating64(v), nil
 	case string:
-		return pg.MakeParameterValueStr(v)
+		return pg.MakeParameterValueStr(v), nil
 	case []byte:
-		return pg.MakeParameterValueBinary(v)
+		return pg.MakeParameterValueBinary(v), nil
 	case []string:
-		return pg.MakeParameterValueArrayStr(toOptionSlice(v))
+		return pg.MakeParameterValueArrayStr(toOptionSlice(v)), nil
 	case Int32Range:
 		witVal, _ := v.Value()
-		return pg.MakeParameterValueRangeInt32(witVal.(wittypes.Tuple2[wittypes.Option[wittypes.Tuple2[int32, pg.RangeBoundKind]], wittypes.Option[wittypes.Tuple2[int32, pg.RangeBoundKind]]]))
+		return pg.MakeParameterValueRangeInt32(witVal.(wittypes.Tuple2[wittypes.Option[wittypes.Tuple2[int32, pg.RangeBoundKind]], wittypes.Option[wittypes.Tuple2[int32, pg.RangeBoundKind]]])), nil
 	case Int64Range:
 		witVal, _ := v.Value()
-		return pg.MakeParameterValueRangeInt64(witVal.(wittypes.Tuple2[wittypes.Option[wittypes.Tuple2[int64, pg.RangeBoundKind]], wittypes.Option[wittypes.Tuple2[int64, pg.RangeBoundKind]]]))
+		return pg.MakeParameterValueRangeInt64(witVal.(wittypes.Tuple2[wittypes.Option[wittypes.Tuple2[int64, pg.RangeBoundKind]], wittypes.Option[wittypes.Tuple2[int64, pg.RangeBoundKind]]])), nil
 	case []int32:
-		return pg.MakeParameterValueArrayInt32(toOptionSlice(v))
+		return pg.MakeParameterValueArrayInt32(toOptionSlice(v)), nil
 	case []int64:
-		return pg.MakeParameterValueArrayInt64(toOptionSlice(v))
+		return pg.MakeParameterValueArrayInt64(toOptionSlice(v)), nil
 	case time.Time:
 		v = v.UTC()
 		return pg.MakeParameterValueDatetime(wittypes.Tuple7[int32, uint8, uint8, uint8, uint8, uint8, uint32]{
@@ -278,46 +500,105 @@ func toRdbmsParameterValue(x any) pg.ParameterValue {
 			F4: uint8(v.Minute()),
 			F5: uint8(v.Second()),
 			F6: uint32(v.Nanosecond()),
-		})
+		}), nil
 	case nil:
-		return pg.MakeParameterValueDbNull()
+		return pg.MakeParameterValueDbNull(), nil
 	case JSONB:
-		return pg.MakeParameterValueJsonb([]byte(v))
+		return pg.MakeParameterValueJsonb([]byte(v)), nil
 	case Date:
 		return pg.MakeParameterValueDate(wittypes.Tuple3[int32, uint8, uint8]{
 			F0: int32(v.Year),
 			F1: uint8(v.Month),
 			F2: uint8(v.Day),
-		})
+		}), nil
 	case Time:
 		return pg.MakeParameterValueTime(wittypes.Tuple4[uint8, uint8, uint8, uint32]{
 			F0: uint8(v.Hour),
 			F1: uint8(v.Minute),
 			F2: uint8(v.Second),
 			F3: uint32(v.Nanosecond),
-		})
+		}), nil
 	case Interval:
 		return pg.MakeParameterValueInterval(pg.Interval{
 			Micros: v.Micros,
 			Days:   v.Days,
 			Months: v.Months,
-		})
+		}), nil
 	case Decimal:
-		return pg.MakeParameterValueDecimal(string(v))
+		return pg.MakeParameterValueDecimal(string(v)), nil
 	case DecimalRange:
 		witVal, _ := v.Value()
-		return pg.MakeParameterValueRangeDecimal(witVal.(wittypes.Tuple2[wittypes.Option[wittypes.Tuple2[string, pg.RangeBoundKind]], wittypes.Option[wittypes.Tuple2[string, pg.RangeBoundKind]]]))
+		return pg.MakeParameterValueRangeDecimal(witVal.(wittypes.Tuple2[wittypes.Option[wittypes.Tuple2[string, pg.RangeBoundKind]], wittypes.Option[wittypes.Tuple2[string, pg.RangeBoundKind]]])), nil
 	case []Decimal:
 		opts := make([]wittypes.Option[string], len(v))
 		for i, d := range v {
 			opts[i] = wittypes.Some(string(d))
 		}
-		return pg.MakeParameterValueArrayDecimal(opts)
+		return pg.MakeParameterValueArrayDecimal(opts), nil
+	case []*int32:
+		return pg.MakeParameterValueArrayInt32(toNullableOptionSlice(v, fromPtr)), nil
+	case []*int64:
+		return pg.MakeParameterValueArrayInt64(toNullableOptionSlice(v, fromPtr)), nil
+	case []*string:
+		return pg.MakeParameterValueArrayStr(toNullableOptionSlice(v, fromPtr)), nil
+	case []*Decimal:
+		return pg.MakeParameterValueArrayDecimal(toNullableOptionSlice(v, func(d *Decimal) (string, bool) {
+			if d == nil {
+				return "", false
+			}
+			return string(*d), true
+		})), nil
+	case []sql.NullInt32:
+		return pg.MakeParameterValueArrayInt32(toNullableOptionSlice(v, func(n sql.NullInt32) (int32, bool) {
+			return n.Int32, n.Valid
+		})), nil
+	case []sql.NullInt64:
+		return pg.MakeParameterValueArrayInt64(toNullableOptionSlice(v, func(n sql.NullInt64) (int64, bool) {
+			return n.Int64, n.Valid
+		})), nil
+	case []sql.NullString:
+		return pg.MakeParameterValueArrayStr(toNullableOptionSlice(v, func(n sql.NullString) (string, bool) {
+			return n.String, n.Valid
+		})), nil
+	case []sql.Null[Decimal]:
+		return pg.MakeParameterValueArrayDecimal(toNullableOptionSlice(v, func(n sql.Null[Decimal]) (string, bool) {
+			return string(n.V), n.Valid
+		})), nil
 	case UUID:
-		return pg.MakeParameterValueUuid(string(v))
+		return pg.MakeParameterValueUuid(string(v)), nil
+	}
+
+	if uuid, ok := uuidString(x); ok {
+		return pg.MakeParameterValueUuid(uuid), nil
+	}
+
+	v, ok, err := spindb.Indirect(x)
+	if err != nil {
+		return pg.ParameterValue{}, err
+	}
+	if !ok {
+		return pg.ParameterValue{}, &ConversionError{Value: x}
+	}
+	return toRdbmsParameterValue(v)
+}
+
+// uuidString formats x as a UUID if it is a named 16-byte array type that
+// implements fmt.Stringer or encoding.TextMarshaler, such as a
+// github.com/google/uuid UUID. Plain [16]byte values, such as MD5 sums, are
+// not treated as UUIDs.
+func uuidString(x any) (string, bool) {
+	switch x.(type) {
+	case fmt.Stringer, encoding.TextMarshaler:
 	default:
-		panic("unknown value type")
+		return "", false
 	}
+	rv := reflect.ValueOf(x)
+	if rv.Kind() != reflect.Array || rv.Len() != 16 || rv.Type().Elem().Kind() != reflect.Uint8 {
+		return "", false
+	}
+	b := make([]byte, 16)
+	reflect.Copy(reflect.ValueOf(b), rv)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), true
 }
 
 // QueryDBError represents a structured PostgreSQL database error returned by the runtime.
@@ -427,13 +708,13 @@ func toRow(row []pg.DbValue) []any {
 		case pg.DbValueRangeDecimal:
 			result[i] = v.RangeDecimal()
 		case pg.DbValueArrayInt32:
-			result[i] = fromOptionSlice(v.ArrayInt32())
+			result[i] = fromArray(v.ArrayInt32())
 		case pg.DbValueArrayInt64:
-			result[i] = fromOptionSlice(v.ArrayInt64())
+			result[i] = fromArray(v.ArrayInt64())
 		case pg.DbValueArrayDecimal:
-			result[i] = fromOptionSlice(v.ArrayDecimal())
+			result[i] = fromArray(v.ArrayDecimal())
 		case pg.DbValueArrayStr:
-			result[i] = fromOptionSlice(v.ArrayStr())
+			result[i] = fromArray(v.ArrayStr())
 		case pg.DbValueInterval:
 			iv := v.Interval()
 			result[i] = Interval{Months: iv.Months, Days: iv.Days, Micros: iv.Micros}
@@ -499,25 +780,71 @@ func colTypeToReflectType(typ uint8) reflect.Type {
 	case pg.DbDataTypeArrayStr:
 		return reflect.TypeFor[[]string]()
 	case pg.DbDataTypeOther:
-		return reflect.TypeFor[any]().Elem()
+		return reflect.TypeFor[any]()
 	}
 	panic("invalid db column type of " + string(typ))
 }
 
-func toOptionSlice[T any](v []T) []wittypes.Option[T] {
-	values := make([]wittypes.Option[T], len(v))
-	for i, x := range v {
-		values[i] = wittypes.Some(x)
+func colTypeToDatabaseTypeName(typ pg.DbDataType) string {
+	switch typ.Tag() {
+	case pg.DbDataTypeBoolean:
+		return "BOOL"
+	case pg.DbDataTypeInt8:
+		return "CHAR"
+	case pg.DbDataTypeInt16:
+		return "INT2"
+	case pg.DbDataTypeInt32:
+		return "INT4"
+	case pg.DbDataTypeInt64:
+		return "INT8"
+	case pg.DbDataTypeFloating32:
+		return "FLOAT4"
+	case pg.DbDataTypeFloating64:
+		return "FLOAT8"
+	case pg.DbDataTypeStr:
+		return "TEXT"
+	case pg.DbDataTypeBinary:
+		return "BYTEA"
+	case pg.DbDataTypeDate:
+		return "DATE"
+	case pg.DbDataTypeTime:
+		return "TIME"
+	case pg.DbDataTypeDatetime:
+		return "TIMESTAMP"
+	case pg.DbDataTypeTimestamp:
+		return "TIMESTAMPTZ"
+	case pg.DbDataTypeUuid:
+		return "UUID"
+	case pg.DbDataTypeJsonb:
+		return "JSONB"
+	case pg.DbDataTypeDecimal:
+		return "NUMERIC"
+	case pg.DbDataTypeRangeInt32:
+		return "INT4RANGE"
+	case pg.DbDataTypeRangeInt64:
+		return "INT8RANGE"
+	case pg.DbDataTypeRangeDecimal:
+		return "NUMRANGE"
+	case pg.DbDataTypeArrayInt32:
+		return "_INT4"
+	case pg.DbDataTypeArrayInt64:
+		return "_INT8"
+	case pg.DbDataTypeArrayDecimal:
+		return "_NUMERIC"
+	case pg.DbDataTypeArrayStr:
+		return "_TEXT"
+	case pg.DbDataTypeInterval:
+		return "INTERVAL"
+	case pg.DbDataTypeOther:
+		return strings.ToUpper(typ.Other())
 	}
-	return values
+	return ""
 }
 
-func fromOptionSlice[T any](v []wittypes.Option[T]) []T {
-	values := make([]T, len(v))
+func toOptionSlice[T any](v []T) []wittypes.Option[T] {
+	values := make([]wittypes.Option[T], len(v))
 	for i, x := range v {
-		if x.IsSome() {
-			values[i] = x.Some()
-		}
+		values[i] = wittypes.Some(x)
 	}
 	return values
 }
@@ -1,8 +1,13 @@
 package pg
 
 import (
+	"crypto/md5"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	pg "github.com/spinframework/spin-go-sdk/v3/imports/spin_postgres_4_2_0_postgres"
 	"github.com/stretchr/testify/assert"
@@ -20,16 +25,6 @@ func TestToOptionSlice(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
-func TestFromOptionSlice(t *testing.T) {
-	got := fromOptionSlice([]wittypes.Option[int]{
-		wittypes.Some(1),
-		wittypes.Some(2),
-		wittypes.Some(3),
-	})
-	want := []int{1, 2, 3}
-	assert.Equal(t, want, got)
-}
-
 func TestToError(t *testing.T) {
 	t.Run("ConnectionFailed", func(t *testing.T) {
 		err := toError(pg.MakeErrorConnectionFailed("connection refused"))
@@ -94,3 +89,168 @@ func TestToError(t *testing.T) {
 		assert.EqualError(t, err, "unknown error")
 	})
 }
+
+type uuidArray [16]byte
+
+func (u uuidArray) String() string { return "unused" }
+
+type namedInt int
+
+type brokenValuer struct{}
+
+func (brokenValuer) Value() (driver.Value, error) {
+	return nil, errors.New("broken")
+}
+
+func TestToRdbmsParameterValue(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want pg.ParameterValue
+	}{{
+		name: "int",
+		src:  42,
+		want: pg.MakeParameterValueInt64(42),
+	}, {
+		name: "named int",
+		src:  namedInt(7),
+		want: pg.MakeParameterValueInt64(7),
+	}, {
+		name: "pointer",
+		src:  ptr("hello"),
+		want: pg.MakeParameterValueStr("hello"),
+	}, {
+		name: "nil pointer",
+		src:  (*int)(nil),
+		want: pg.MakeParameterValueDbNull(),
+	}, {
+		name: "pointer to Date",
+		src:  &Date{Year: 2024, Month: time.March, Day: 15},
+		want: pg.MakeParameterValueDate(wittypes.Tuple3[int32, uint8, uint8]{F0: 2024, F1: 3, F2: 15}),
+	}, {
+		name: "valid sql.NullInt32",
+		src:  sql.NullInt32{Int32: 5, Valid: true},
+		want: pg.MakeParameterValueInt64(5),
+	}, {
+		name: "null sql.NullString",
+		src:  sql.NullString{},
+		want: pg.MakeParameterValueDbNull(),
+	}, {
+		name: "UUID-like array",
+		src:  uuidArray{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+		want: pg.MakeParameterValueUuid("123e4567-e89b-12d3-a456-426614174000"),
+	}, {
+		name: "already converted",
+		src:  pg.MakeParameterValueBoolean(true),
+		want: pg.MakeParameterValueBoolean(true),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toRdbmsParameterValue(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckNamedValue(t *testing.T) {
+	c := &conn{}
+
+	t.Run("converts", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 1, Value: int32(3)}
+		require.NoError(t, c.CheckNamedValue(nv))
+		assert.Equal(t, pg.MakeParameterValueInt32(3), nv.Value)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 2, Name: "when", Value: struct{}{}}
+		err := c.CheckNamedValue(nv)
+
+		var convErr *ConversionError
+		require.True(t, errors.As(err, &convErr))
+		assert.Equal(t, 2, convErr.Ordinal)
+		assert.Equal(t, "when", convErr.Name)
+		assert.Equal(t, struct{}{}, convErr.Value)
+		assert.NoError(t, convErr.Err)
+	})
+
+	t.Run("16-byte array is not a UUID", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 1, Value: md5.Sum([]byte("spin"))}
+		err := c.CheckNamedValue(nv)
+
+		var convErr *ConversionError
+		require.True(t, errors.As(err, &convErr))
+	})
+
+	t.Run("valuer error", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 1, Value: brokenValuer{}}
+		err := c.CheckNamedValue(nv)
+
+		var convErr *ConversionError
+		require.True(t, errors.As(err, &convErr))
+		assert.EqualError(t, convErr.Err, "broken")
+	})
+}
+
+func TestFromArray(t *testing.T) {
+	t.Run("without NULL", func(t *testing.T) {
+		got := fromArray([]wittypes.Option[int32]{wittypes.Some[int32](1), wittypes.Some[int32](2)})
+		assert.Equal(t, []int32{1, 2}, got)
+	})
+
+	t.Run("with NULL", func(t *testing.T) {
+		got := fromArray([]wittypes.Option[string]{wittypes.Some("a"), wittypes.None[string]()})
+		assert.Equal(t, []*string{ptr("a"), nil}, got)
+	})
+}
+
+func TestRowsColumnTypes(t *testing.T) {
+	r := &rows{columnType: []pg.DbDataType{
+		pg.MakeDbDataTypeInt32(),
+		pg.MakeDbDataTypeDatetime(),
+		pg.MakeDbDataTypeArrayStr(),
+		pg.MakeDbDataTypeOther("citext"),
+	}}
+
+	var names []string
+	for i := range r.columnType {
+		names = append(names, r.ColumnTypeDatabaseTypeName(i))
+	}
+	assert.Equal(t, []string{"INT4", "TIMESTAMP", "_TEXT", "CITEXT"}, names)
+
+	assert.Equal(t, reflect.TypeFor[time.Time](), r.ColumnTypeScanType(1))
+	assert.Equal(t, reflect.TypeFor[any](), r.ColumnTypeScanType(3))
+
+	// The scan type of an array column matches the values it is read as
+	// unless they contain NULL elements, which need Array to be scanned.
+	withoutNull := toRow([]pg.DbValue{pg.MakeDbValueArrayStr([]wittypes.Option[string]{wittypes.Some("a")})})
+	withNull := toRow([]pg.DbValue{pg.MakeDbValueArrayStr([]wittypes.Option[string]{wittypes.None[string]()})})
+	assert.Equal(t, r.ColumnTypeScanType(2), reflect.TypeOf(withoutNull[0]))
+	assert.Equal(t, reflect.TypeFor[[]*string](), reflect.TypeOf(withNull[0]))
+
+	_, ok := r.ColumnTypeNullable(0)
+	assert.False(t, ok)
+	_, _, ok = r.ColumnTypePrecisionScale(0)
+	assert.False(t, ok)
+}
+
+func TestToRow_Times(t *testing.T) {
+	row := toRow([]pg.DbValue{
+		pg.MakeDbValueDate(wittypes.Tuple3[int32, uint8, uint8]{F0: 2024, F1: 3, F2: 15}),
+		pg.MakeDbValueTime(wittypes.Tuple4[uint8, uint8, uint8, uint32]{F0: 10, F1: 30, F2: 5, F3: 1000}),
+		pg.MakeDbValueDatetime(wittypes.Tuple7[int32, uint8, uint8, uint8, uint8, uint8, uint32]{F0: 2024, F1: 3, F2: 15, F3: 10, F4: 30, F5: 5}),
+	})
+
+	for _, v := range row {
+		assert.IsType(t, time.Time{}, v)
+	}
+
+	var d Date
+	require.NoError(t, d.Scan(row[0]))
+	assert.Equal(t, Date{Year: 2024, Month: time.March, Day: 15}, d)
+
+	var tm Time
+	require.NoError(t, tm.Scan(row[1]))
+	assert.Equal(t, Time{Hour: 10, Minute: 30, Second: 5, Nanosecond: 1000}, tm)
+}
@@ -0,0 +1,47 @@
+package pg
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSQLStateTable checks the generated table: every code is five
+// characters long, appears once, and belongs to the class of the section it
+// is listed under.
+func TestSQLStateTable(t *testing.T) {
+	f, err := os.Open("sqlstate.go")
+	require.NoError(t, err)
+	defer f.Close()
+
+	classRe := regexp.MustCompile(`^\t// Class ([0-9A-Z]{2}) - `)
+	codeRe := regexp.MustCompile(`^\t(Code\w+)\s+= "([^"]*)"$`)
+
+	class := ""
+	seen := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := classRe.FindStringSubmatch(line); m != nil {
+			class = m[1]
+			continue
+		}
+		m := codeRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name, code := m[1], m[2]
+		assert.Regexp(t, `^[0-9A-Z]{5}$`, code, name)
+		assert.Equal(t, class, code[:2], "%s is not in class %s", name, class)
+		if other, ok := seen[code]; ok {
+			t.Errorf("%s and %s have the same code %s", other, name, code)
+		}
+		seen[code] = name
+	}
+	require.NoError(t, scanner.Err())
+	assert.Len(t, seen, 261)
+}
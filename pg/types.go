@@ -3,6 +3,8 @@ package pg
 import (
 	"database/sql/driver"
 	"fmt"
+	"math"
+	"math/big"
 	"time"
 
 	pg "github.com/spinframework/spin-go-sdk/v3/imports/spin_postgres_4_2_0_postgres"
@@ -33,7 +35,16 @@ import (
 // | `[]int64`               | array-int64(...)                              | INT8[]                       |
 // | `[]string`              | array-str(...)                                | TEXT[]                       |
 // | `[]Decimal`             | array-decimal(...)                            | NUMERIC[]                    |
+// | `NullInt32Array`        | array-int32(...)                              | INT4[] with NULL elements    |
+// | `NullInt64Array`        | array-int64(...)                              | INT8[] with NULL elements    |
+// | `NullStringArray`       | array-str(...)                                | TEXT[] with NULL elements    |
+// | `NullDecimalArray`      | array-decimal(...)                            | NUMERIC[] with NULL elements |
 // | `Interval`              | interval(interval)                            | INTERVAL                     |
+//
+// Arrays may also be passed as slices of pointers, such as []*string, with nil
+// for NULL elements. Arrays read from a row are []T, such as []int32 or
+// []string for NUMERIC[], or []*T if they contain a NULL element; use Array or
+// the Null*Array types to scan arrays that may contain NULLs.
 
 // Date represents a PostgreSQL date value.
 type Date struct {
@@ -66,6 +77,11 @@ func (d Date) Value() (driver.Value, error) {
 	}, nil
 }
 
+// Time returns midnight at the start of the date in loc.
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
 // Time represents a PostgreSQL time value (time of day without date).
 type Time struct {
 	Hour       int
@@ -116,6 +132,8 @@ func (iv *Interval) Scan(src any) error {
 	switch src := src.(type) {
 	case Interval:
 		*iv = src
+	case time.Duration:
+		*iv = Interval{Micros: src.Microseconds()}
 	case nil:
 		*iv = Interval{}
 	default:
@@ -133,6 +151,29 @@ func (iv Interval) Value() (driver.Value, error) {
 	}, nil
 }
 
+// Duration returns the length of the interval, counting a day as 24 hours
+// and a month as 30 days, as PostgreSQL does when justifying intervals. The
+// result saturates at the limits of time.Duration.
+func (iv Interval) Duration() time.Duration {
+	const (
+		microsPerDay   = int64(24 * time.Hour / time.Microsecond)
+		microsPerMonth = 30 * microsPerDay
+	)
+
+	micros := new(big.Int).SetInt64(iv.Micros)
+	micros.Add(micros, new(big.Int).Mul(big.NewInt(int64(iv.Days)), big.NewInt(microsPerDay)))
+	micros.Add(micros, new(big.Int).Mul(big.NewInt(int64(iv.Months)), big.NewInt(microsPerMonth)))
+
+	nanos := micros.Mul(micros, big.NewInt(int64(time.Microsecond)))
+	switch {
+	case nanos.Cmp(big.NewInt(math.MaxInt64)) > 0:
+		return math.MaxInt64
+	case nanos.Cmp(big.NewInt(math.MinInt64)) < 0:
+		return math.MinInt64
+	}
+	return time.Duration(nanos.Int64())
+}
+
 // JSONB represents a PostgreSQL jsonb value.
 type JSONB []byte
 
@@ -2,6 +2,7 @@ package pg
 
 import (
 	"database/sql/driver"
+	"math"
 	"testing"
 	"time"
 
@@ -120,6 +121,12 @@ func TestDate_RoundTrip(t *testing.T) {
 	assert.Equal(t, original, recovered)
 }
 
+func TestDate_Time(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	d := Date{Year: 2024, Month: time.February, Day: 29}
+	assert.Equal(t, time.Date(2024, time.February, 29, 0, 0, 0, 0, loc), d.Time(loc))
+}
+
 func TestTime_Scan(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -189,6 +196,10 @@ func TestInterval_Scan(t *testing.T) {
 		name: "micros only",
 		src:  Interval{Micros: 3600000000},
 		want: Interval{Micros: 3600000000},
+	}, {
+		name: "time.Duration",
+		src:  90 * time.Minute,
+		want: Interval{Micros: 5400000000},
 	}, {
 		name: "nil src",
 		src:  nil,
@@ -221,6 +232,36 @@ func TestInterval_Value(t *testing.T) {
 	}), got)
 }
 
+func TestInterval_Duration(t *testing.T) {
+	tests := []struct {
+		name string
+		iv   Interval
+		want time.Duration
+	}{{
+		name: "micros",
+		iv:   Interval{Micros: 1500},
+		want: 1500 * time.Microsecond,
+	}, {
+		name: "days and months",
+		iv:   Interval{Months: 1, Days: 2, Micros: -3600000000},
+		want: 32*24*time.Hour - time.Hour,
+	}, {
+		name: "saturates",
+		iv:   Interval{Months: math.MaxInt32},
+		want: math.MaxInt64,
+	}, {
+		name: "saturates negative",
+		iv:   Interval{Days: math.MinInt32, Micros: math.MinInt64},
+		want: math.MinInt64,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.iv.Duration())
+		})
+	}
+}
+
 func TestInterval_RoundTrip(t *testing.T) {
 	original := Interval{Months: 1, Days: 15, Micros: 43200000000}
 
@@ -610,3 +651,171 @@ func TestDecimalRange_RoundTrip(t *testing.T) {
 	recovered.Scan(witVal)
 	assert.Equal(t, original, recovered)
 }
+
+// echoArray simulates the host returning an array parameter as a column
+// value.
+func echoArray(t *testing.T, v driver.Value) any {
+	t.Helper()
+
+	p, ok := v.(pg.ParameterValue)
+	require.True(t, ok, "Value returned %T", v)
+
+	var db pg.DbValue
+	switch p.Tag() {
+	case pg.ParameterValueArrayInt32:
+		db = pg.MakeDbValueArrayInt32(p.ArrayInt32())
+	case pg.ParameterValueArrayInt64:
+		db = pg.MakeDbValueArrayInt64(p.ArrayInt64())
+	case pg.ParameterValueArrayDecimal:
+		db = pg.MakeDbValueArrayDecimal(p.ArrayDecimal())
+	case pg.ParameterValueArrayStr:
+		db = pg.MakeDbValueArrayStr(p.ArrayStr())
+	default:
+		t.Fatalf("unexpected parameter value tag %d", p.Tag())
+	}
+	return toRow([]pg.DbValue{db})[0]
+}
+
+func TestArray_Value(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want pg.ParameterValue
+	}{{
+		name: "[]int32",
+		src:  []int32{1, 2},
+		want: pg.MakeParameterValueArrayInt32([]wittypes.Option[int32]{wittypes.Some[int32](1), wittypes.Some[int32](2)}),
+	}, {
+		name: "[]*string",
+		src:  []*string{ptr("a"), nil},
+		want: pg.MakeParameterValueArrayStr([]wittypes.Option[string]{wittypes.Some("a"), wittypes.None[string]()}),
+	}, {
+		name: "pointer to []*int64",
+		src:  &[]*int64{nil, ptr[int64](7)},
+		want: pg.MakeParameterValueArrayInt64([]wittypes.Option[int64]{wittypes.None[int64](), wittypes.Some[int64](7)}),
+	}, {
+		name: "NullInt32Array",
+		src:  NullInt32Array{{Int32: 3, Valid: true}, {}},
+		want: pg.MakeParameterValueArrayInt32([]wittypes.Option[int32]{wittypes.Some[int32](3), wittypes.None[int32]()}),
+	}, {
+		name: "NullDecimalArray",
+		src:  NullDecimalArray{{}, {V: "1.5", Valid: true}},
+		want: pg.MakeParameterValueArrayDecimal([]wittypes.Option[string]{wittypes.None[string](), wittypes.Some("1.5")}),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Array(tt.src).Value()
+			require.NoError(t, err)
+			assert.Equal(t, driver.Value(tt.want), got)
+		})
+	}
+}
+
+func TestArray_Scan(t *testing.T) {
+	t.Run("NULL array", func(t *testing.T) {
+		got := []int32{1}
+		require.NoError(t, Array(&got).Scan(nil))
+		assert.Nil(t, got)
+	})
+
+	t.Run("NULL element into non-nullable slice", func(t *testing.T) {
+		var got []int32
+		err := Array(&got).Scan([]*int32{ptr[int32](1), nil})
+		assert.EqualError(t, err, "pg: cannot scan element 1 into *[]int32: NULL element in array of int32")
+	})
+
+	t.Run("wrong element type", func(t *testing.T) {
+		var got []string
+		err := Array(&got).Scan([]int64{1})
+		assert.EqualError(t, err, "pg: cannot scan []int64 into *[]string")
+	})
+
+	t.Run("unsupported destination", func(t *testing.T) {
+		var got []float64
+		err := Array(&got).Scan([]int64{1})
+		assert.EqualError(t, err, "pg: cannot scan array into *[]float64")
+	})
+}
+
+func TestArray_RoundTrip(t *testing.T) {
+	t.Run("[]int32", func(t *testing.T) {
+		original := []int32{1, 2, 3}
+		val, err := Array(original).Value()
+		require.NoError(t, err)
+
+		var recovered []int32
+		require.NoError(t, Array(&recovered).Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+
+	t.Run("[]*string", func(t *testing.T) {
+		original := []*string{ptr("a"), nil, ptr("")}
+		val, err := Array(original).Value()
+		require.NoError(t, err)
+
+		var recovered []*string
+		require.NoError(t, Array(&recovered).Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+
+	t.Run("[]*Decimal", func(t *testing.T) {
+		original := []*Decimal{nil, ptr(Decimal("9.99"))}
+		val, err := Array(original).Value()
+		require.NoError(t, err)
+
+		var recovered []*Decimal
+		require.NoError(t, Array(&recovered).Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+
+	t.Run("NullInt32Array", func(t *testing.T) {
+		original := NullInt32Array{{Int32: 1, Valid: true}, {}, {Int32: 0, Valid: true}}
+		val, err := original.Value()
+		require.NoError(t, err)
+
+		var recovered NullInt32Array
+		require.NoError(t, recovered.Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+
+	t.Run("NullInt64Array", func(t *testing.T) {
+		original := NullInt64Array{{}, {Int64: 1 << 40, Valid: true}}
+		val, err := original.Value()
+		require.NoError(t, err)
+
+		var recovered NullInt64Array
+		require.NoError(t, recovered.Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+
+	t.Run("NullStringArray", func(t *testing.T) {
+		original := NullStringArray{{String: "x", Valid: true}, {}}
+		val, err := original.Value()
+		require.NoError(t, err)
+
+		var recovered NullStringArray
+		require.NoError(t, recovered.Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+
+	t.Run("NullDecimalArray", func(t *testing.T) {
+		original := NullDecimalArray{{V: "0.1", Valid: true}, {}}
+		val, err := original.Value()
+		require.NoError(t, err)
+
+		var recovered NullDecimalArray
+		require.NoError(t, recovered.Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		original := NullStringArray{}
+		val, err := original.Value()
+		require.NoError(t, err)
+
+		var recovered NullStringArray
+		require.NoError(t, recovered.Scan(echoArray(t, val)))
+		assert.Equal(t, original, recovered)
+	})
+}
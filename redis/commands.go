@@ -0,0 +1,481 @@
+package redis
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// The methods in this file are built on Execute. The Spin host flattens
+// nested array replies into a single list of results, and omits nil and "OK"
+// replies, so each method checks the shape of the results it decodes.
+
+// ResultTypeError is returned when a command returns a result of a different
+// kind than the method expected.
+type ResultTypeError struct {
+	// Command is the Redis command, such as "HGET".
+	Command string
+	// Want is the kind of result expected.
+	Want ResultKind
+	// Got is the kind of result returned.
+	Got ResultKind
+}
+
+func (e *ResultTypeError) Error() string {
+	return fmt.Sprintf("redis: %s returned a %s result, want %s", e.Command, e.Got, e.Want)
+}
+
+// Z is a member of a sorted set.
+type Z struct {
+	Score  float64
+	Member string
+}
+
+// HashField is a field of a hash.
+type HashField struct {
+	Field string
+	Value []byte
+}
+
+// ScanOptions filters the keys returned by Scan and the members returned by
+// SScan and HScan.
+type ScanOptions struct {
+	// Match is a glob-style pattern that keys or members must match, such as
+	// "user:*". Empty matches everything.
+	Match string
+	// Count hints how many keys or members to fetch per round trip. Zero uses
+	// the server's default.
+	Count int64
+	// Type restricts Scan to keys holding values of a type, such as "hash".
+	// It is ignored by SScan and HScan.
+	Type string
+}
+
+const (
+	// NoExpiry is the TTL of a key that exists but has no expiry.
+	NoExpiry time.Duration = -1
+	// NoKey is the TTL of a key that does not exist.
+	NoKey time.Duration = -2
+)
+
+// HSet sets fields in the hash stored at key, returning the number of fields
+// that were added rather than updated.
+func (c *Client) HSet(key string, fields map[string][]byte) (int64, error) {
+	return c.intCommand("HSET", hsetArgs(key, fields)...)
+}
+
+// HGet returns the value of a field in the hash stored at key, or nil if the
+// field or key does not exist.
+func (c *Client) HGet(key, field string) ([]byte, error) {
+	return c.optionalBinaryCommand("HGET", key, field)
+}
+
+// HGetAll returns all the fields of the hash stored at key. It returns an
+// empty map if the key does not exist.
+func (c *Client) HGetAll(key string) (map[string][]byte, error) {
+	values, err := c.binaryCommand("HGETALL", key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHashFields(values)
+}
+
+// LPush prepends values to the list stored at key, returning the length of
+// the list.
+func (c *Client) LPush(key string, values ...[]byte) (int64, error) {
+	return c.intCommand("LPUSH", lpushArgs(key, values)...)
+}
+
+// RPop removes and returns the last element of the list stored at key, or nil
+// if the list is empty.
+func (c *Client) RPop(key string) ([]byte, error) {
+	return c.optionalBinaryCommand("RPOP", key)
+}
+
+// LRange returns the elements of the list stored at key between the start and
+// stop indexes, inclusive. Negative indexes count from the end of the list.
+func (c *Client) LRange(key string, start, stop int64) ([][]byte, error) {
+	return c.binaryCommand("LRANGE", key, start, stop)
+}
+
+// ZAdd adds members to the sorted set stored at key, or updates their scores,
+// returning the number of members added.
+func (c *Client) ZAdd(key string, members ...Z) (int64, error) {
+	return c.intCommand("ZADD", zaddArgs(key, members)...)
+}
+
+// ZRange returns the members of the sorted set stored at key between the start
+// and stop ranks, inclusive, ordered from the lowest score. Negative ranks
+// count from the highest score.
+func (c *Client) ZRange(key string, start, stop int64) ([]string, error) {
+	values, err := c.binaryCommand("ZRANGE", key, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return toStrings(values), nil
+}
+
+// ZRangeWithScores is like ZRange, but also returns the members' scores.
+func (c *Client) ZRangeWithScores(key string, start, stop int64) ([]Z, error) {
+	values, err := c.binaryCommand("ZRANGE", key, start, stop, "WITHSCORES")
+	if err != nil {
+		return nil, err
+	}
+	return decodeMemberScores(values)
+}
+
+// ZScore returns the score of a member of the sorted set stored at key. It
+// returns false if the member or key does not exist.
+func (c *Client) ZScore(key, member string) (float64, bool, error) {
+	value, err := c.optionalBinaryCommand("ZSCORE", key, member)
+	if err != nil || value == nil {
+		return 0, false, err
+	}
+	score, err := parseScore("ZSCORE", value)
+	if err != nil {
+		return 0, false, err
+	}
+	return score, true, nil
+}
+
+// Expire sets a timeout on key, after which it is deleted, using PEXPIRE. The
+// timeout is rounded down to whole milliseconds, and a timeout of zero or less
+// deletes the key. It returns false if the key does not exist.
+func (c *Client) Expire(key string, ttl time.Duration) (bool, error) {
+	n, err := c.intCommand("PEXPIRE", key, ttl.Milliseconds())
+	return n == 1, err
+}
+
+// TTL returns the remaining time to live of key, to the millisecond, using
+// PTTL. It returns NoExpiry if the key has no timeout, or NoKey if the key
+// does not exist.
+func (c *Client) TTL(key string) (time.Duration, error) {
+	n, err := c.intCommand("PTTL", key)
+	if err != nil {
+		return 0, err
+	}
+	return decodeTTL(n), nil
+}
+
+// decodeTTL converts a reply of PTTL to a duration.
+func decodeTTL(n int64) time.Duration {
+	switch n {
+	case -1:
+		return NoExpiry
+	case -2:
+		return NoKey
+	default:
+		return time.Duration(n) * time.Millisecond
+	}
+}
+
+// SetEx sets the value of key with a timeout, using PSETEX. The timeout is
+// rounded down to whole milliseconds and must be at least a millisecond.
+func (c *Client) SetEx(key string, value []byte, ttl time.Duration) error {
+	return c.okCommand("PSETEX", key, ttl.Milliseconds(), value)
+}
+
+// SetNX sets the value of key only if the key does not exist, returning
+// whether it was set.
+func (c *Client) SetNX(key string, value []byte) (bool, error) {
+	n, err := c.intCommand("SETNX", key, value)
+	return n == 1, err
+}
+
+// MGet returns the values of keys, with nil for keys that do not exist.
+func (c *Client) MGet(keys ...string) ([][]byte, error) {
+	values, err := c.binaryCommand("MGET", toAnys(keys)...)
+	if err != nil {
+		return nil, err
+	}
+	return mget(keys, values, func(key string) ([]byte, error) {
+		return c.optionalBinaryCommand("GET", key)
+	})
+}
+
+// mget matches the values returned by MGET to keys. The host omits nil
+// replies, so when some keys are missing there is no telling which values
+// belong to which keys, and each key is fetched again with get.
+func mget(keys []string, values [][]byte, get func(key string) ([]byte, error)) ([][]byte, error) {
+	if len(values) == len(keys) {
+		return values, nil
+	}
+
+	values = make([][]byte, len(keys))
+	for i, key := range keys {
+		var err error
+		if values[i], err = get(key); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// MSet sets the values of several keys at once.
+func (c *Client) MSet(values map[string][]byte) error {
+	var args []any
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		args = append(args, key, values[key])
+	}
+	return c.okCommand("MSET", args...)
+}
+
+// Scan returns an iterator over the keys in the database, fetched with SCAN.
+// As with SCAN, a key may be yielded more than once, and keys added or
+// removed during the iteration may or may not be yielded. An error ends the
+// iteration.
+func (c *Client) Scan(opts ScanOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for values, err := range c.scan("SCAN", nil, opts) {
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, v := range values {
+				if !yield(string(v), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SScan returns an iterator over the members of the set stored at key,
+// fetched with SSCAN. It has the same guarantees as Scan.
+func (c *Client) SScan(key string, opts ScanOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for values, err := range c.scan("SSCAN", []any{key}, opts) {
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, v := range values {
+				if !yield(string(v), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// HScan returns an iterator over the fields of the hash stored at key,
+// fetched with HSCAN. It has the same guarantees as Scan.
+func (c *Client) HScan(key string, opts ScanOptions) iter.Seq2[HashField, error] {
+	return func(yield func(HashField, error) bool) {
+		for values, err := range c.scan("HSCAN", []any{key}, opts) {
+			if err == nil && len(values)%2 != 0 {
+				err = fmt.Errorf("redis: HSCAN returned %d results, want field-value pairs", len(values))
+			}
+			if err != nil {
+				yield(HashField{}, err)
+				return
+			}
+			for i := 0; i < len(values); i += 2 {
+				if !yield(HashField{Field: string(values[i]), Value: values[i+1]}, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// scan runs a SCAN-family command until the cursor returns to zero, yielding
+// the elements of each reply.
+func (c *Client) scan(command string, args []any, opts ScanOptions) iter.Seq2[[][]byte, error] {
+	return func(yield func([][]byte, error) bool) {
+		cursor := "0"
+		for {
+			cmdArgs := append(slices.Clone(args), cursor)
+			if opts.Match != "" {
+				cmdArgs = append(cmdArgs, "MATCH", opts.Match)
+			}
+			if opts.Count > 0 {
+				cmdArgs = append(cmdArgs, "COUNT", opts.Count)
+			}
+			if opts.Type != "" && command == "SCAN" {
+				cmdArgs = append(cmdArgs, "TYPE", opts.Type)
+			}
+
+			values, err := c.binaryCommand(command, cmdArgs...)
+			if err == nil && len(values) == 0 {
+				err = fmt.Errorf("redis: %s returned no cursor", command)
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			cursor = string(values[0])
+			if !yield(values[1:], nil) || cursor == "0" {
+				return
+			}
+		}
+	}
+}
+
+// intCommand runs a command that returns an integer.
+func (c *Client) intCommand(command string, args ...any) (int64, error) {
+	return decode(command, decodeInt)(c.Execute(command, args...))
+}
+
+// optionalBinaryCommand runs a command that returns a bulk string or nil.
+func (c *Client) optionalBinaryCommand(command string, args ...any) ([]byte, error) {
+	return decode(command, decodeOptionalBinary)(c.Execute(command, args...))
+}
+
+// binaryCommand runs a command that returns bulk strings.
+func (c *Client) binaryCommand(command string, args ...any) ([][]byte, error) {
+	return decode(command, decodeBinaries)(c.Execute(command, args...))
+}
+
+// okCommand runs a command that returns "OK".
+func (c *Client) okCommand(command string, args ...any) error {
+	_, err := decode(command, decodeOK)(c.Execute(command, args...))
+	return err
+}
+
+// decode adapts a decoder to the results of Execute.
+func decode[T any](command string, decoder func(string, []*Result) (T, error)) func([]*Result, error) (T, error) {
+	return func(results []*Result, err error) (T, error) {
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return decoder(command, results)
+	}
+}
+
+func decodeInt(command string, results []*Result) (int64, error) {
+	if len(results) != 1 {
+		return 0, fmt.Errorf("redis: %s returned %d results, want 1", command, len(results))
+	}
+	if results[0].Kind != ResultKindInt64 {
+		return 0, &ResultTypeError{Command: command, Want: ResultKindInt64, Got: results[0].Kind}
+	}
+	return results[0].Val.(int64), nil
+}
+
+func decodeBool(command string, results []*Result) (bool, error) {
+	n, err := decodeInt(command, results)
+	return n == 1, err
+}
+
+func decodeOptionalBinary(command string, results []*Result) ([]byte, error) {
+	values, err := decodeBinaries(command, results)
+	if err != nil {
+		return nil, err
+	}
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		return values[0], nil
+	default:
+		return nil, fmt.Errorf("redis: %s returned %d results, want 1", command, len(values))
+	}
+}
+
+func decodeBinaries(command string, results []*Result) ([][]byte, error) {
+	values := make([][]byte, 0, len(results))
+	for _, r := range results {
+		switch r.Kind {
+		case ResultKindBinary:
+			values = append(values, r.Val.([]byte))
+		case ResultKindNil:
+			values = append(values, nil)
+		default:
+			return nil, &ResultTypeError{Command: command, Want: ResultKindBinary, Got: r.Kind}
+		}
+	}
+	return values, nil
+}
+
+func decodeOK(command string, results []*Result) (struct{}, error) {
+	for _, r := range results {
+		if r.Kind != ResultKindStatus {
+			return struct{}{}, &ResultTypeError{Command: command, Want: ResultKindStatus, Got: r.Kind}
+		}
+		if status := r.Val.(string); status != "OK" {
+			return struct{}{}, fmt.Errorf("redis: %s returned status %q", command, status)
+		}
+	}
+	return struct{}{}, nil
+}
+
+// decodeHashFields decodes the field-value pairs returned by HGETALL.
+func decodeHashFields(values [][]byte) (map[string][]byte, error) {
+	if len(values)%2 != 0 {
+		return nil, fmt.Errorf("redis: HGETALL returned %d results, want field-value pairs", len(values))
+	}
+
+	fields := make(map[string][]byte, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		fields[string(values[i])] = values[i+1]
+	}
+	return fields, nil
+}
+
+// decodeMemberScores decodes the member-score pairs returned by ZRANGE
+// WITHSCORES.
+func decodeMemberScores(values [][]byte) ([]Z, error) {
+	if len(values)%2 != 0 {
+		return nil, fmt.Errorf("redis: ZRANGE returned %d results, want member-score pairs", len(values))
+	}
+
+	members := make([]Z, 0, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		score, err := parseScore("ZRANGE", values[i+1])
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, Z{Score: score, Member: string(values[i])})
+	}
+	return members, nil
+}
+
+func hsetArgs(key string, fields map[string][]byte) []any {
+	args := []any{key}
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		args = append(args, field, fields[field])
+	}
+	return args
+}
+
+func lpushArgs(key string, values [][]byte) []any {
+	args := []any{key}
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
+
+func zaddArgs(key string, members []Z) []any {
+	args := []any{key}
+	for _, m := range members {
+		args = append(args, formatScore(m.Score), m.Member)
+	}
+	return args
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'g', -1, 64)
+}
+
+func parseScore(command string, value []byte) (float64, error) {
+	score, err := strconv.ParseFloat(string(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("redis: %s returned invalid score %q", command, value)
+	}
+	return score, nil
+}
+
+func toStrings(values [][]byte) []string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = string(v)
+	}
+	return strs
+}
@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func status(s string) *Result { return &Result{Kind: ResultKindStatus, Val: s} }
+func integer(n int64) *Result { return &Result{Kind: ResultKindInt64, Val: n} }
+func binary(s string) *Result { return &Result{Kind: ResultKindBinary, Val: []byte(s)} }
+func nilResult() *Result      { return &Result{Kind: ResultKindNil} }
+
+func bytesOf(s ...string) [][]byte {
+	values := make([][]byte, len(s))
+	for i, v := range s {
+		values[i] = []byte(v)
+	}
+	return values
+}
+
+func TestDecodeInt(t *testing.T) {
+	n, err := decodeInt("INCR", []*Result{integer(3)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	_, err = decodeInt("INCR", nil)
+	assert.EqualError(t, err, "redis: INCR returned 0 results, want 1")
+
+	_, err = decodeInt("INCR", []*Result{integer(1), integer(2)})
+	assert.EqualError(t, err, "redis: INCR returned 2 results, want 1")
+
+	_, err = decodeInt("INCR", []*Result{binary("3")})
+	var typeErr *ResultTypeError
+	require.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, &ResultTypeError{Command: "INCR", Want: ResultKindInt64, Got: ResultKindBinary}, typeErr)
+}
+
+func TestDecodeBinaries(t *testing.T) {
+	values, err := decodeBinaries("LRANGE", []*Result{binary("a"), nilResult(), binary("")})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), nil, []byte("")}, values)
+
+	values, err = decodeBinaries("LRANGE", nil)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	_, err = decodeBinaries("LRANGE", []*Result{binary("a"), integer(1)})
+	assert.EqualError(t, err, "redis: LRANGE returned a int64 result, want binary")
+}
+
+func TestDecodeOptionalBinary(t *testing.T) {
+	value, err := decodeOptionalBinary("GET", nil)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = decodeOptionalBinary("GET", []*Result{binary("v")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v"), value)
+
+	_, err = decodeOptionalBinary("GET", []*Result{binary("a"), binary("b")})
+	assert.EqualError(t, err, "redis: GET returned 2 results, want 1")
+}
+
+func TestDecodeOK(t *testing.T) {
+	// The host omits "OK" replies, so no results is success too.
+	_, err := decodeOK("SET", nil)
+	assert.NoError(t, err)
+
+	_, err = decodeOK("SET", []*Result{status("OK")})
+	assert.NoError(t, err)
+
+	_, err = decodeOK("SET", []*Result{status("QUEUED")})
+	assert.EqualError(t, err, `redis: SET returned status "QUEUED"`)
+
+	_, err = decodeOK("SET", []*Result{integer(1)})
+	assert.EqualError(t, err, "redis: SET returned a int64 result, want status")
+}
+
+func TestMGet(t *testing.T) {
+	get := func(values map[string]string) func(string) ([]byte, error) {
+		return func(key string) ([]byte, error) {
+			if v, ok := values[key]; ok {
+				return []byte(v), nil
+			}
+			return nil, nil
+		}
+	}
+
+	t.Run("all keys present", func(t *testing.T) {
+		values, err := mget([]string{"a", "b"}, bytesOf("1", "2"), func(string) ([]byte, error) {
+			t.Fatal("unexpected GET")
+			return nil, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, bytesOf("1", "2"), values)
+	})
+
+	t.Run("missing keys are fetched one by one", func(t *testing.T) {
+		values, err := mget([]string{"a", "b", "c"}, bytesOf("1", "3"), get(map[string]string{"a": "1", "c": "3"}))
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("1"), nil, []byte("3")}, values)
+	})
+
+	t.Run("GET fails", func(t *testing.T) {
+		_, err := mget([]string{"a", "b"}, nil, func(string) ([]byte, error) {
+			return nil, errors.New("redis: type error")
+		})
+		assert.EqualError(t, err, "redis: type error")
+	})
+}
+
+func TestDecodeHashFields(t *testing.T) {
+	fields, err := decodeHashFields(bytesOf("name", "Kiki", "prey", "cicadas"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"name": []byte("Kiki"), "prey": []byte("cicadas")}, fields)
+
+	_, err = decodeHashFields(bytesOf("name", "Kiki", "prey"))
+	assert.EqualError(t, err, "redis: HGETALL returned 3 results, want field-value pairs")
+}
+
+func TestDecodeMemberScores(t *testing.T) {
+	members, err := decodeMemberScores(bytesOf("a", "1", "b", "2.5"))
+	require.NoError(t, err)
+	assert.Equal(t, []Z{{Score: 1, Member: "a"}, {Score: 2.5, Member: "b"}}, members)
+
+	_, err = decodeMemberScores(bytesOf("a", "1", "b"))
+	assert.EqualError(t, err, "redis: ZRANGE returned 3 results, want member-score pairs")
+
+	_, err = decodeMemberScores(bytesOf("a", "high"))
+	assert.EqualError(t, err, `redis: ZRANGE returned invalid score "high"`)
+}
+
+func TestDecodeTTL(t *testing.T) {
+	assert.Equal(t, 1500*time.Millisecond, decodeTTL(1500))
+	assert.Equal(t, 250*time.Millisecond, decodeTTL(250))
+	assert.Equal(t, time.Duration(0), decodeTTL(0))
+	assert.Equal(t, NoExpiry, decodeTTL(-1))
+	assert.Equal(t, NoKey, decodeTTL(-2))
+}
+
+func TestQueueTTLs(t *testing.T) {
+	var q queue
+	command, args := q.Expire("k", 1500*time.Millisecond).cmd()
+	assert.Equal(t, "PEXPIRE", command)
+	assert.Equal(t, []any{"k", int64(1500)}, args)
+
+	command, args = q.SetEx("k", []byte("v"), 250*time.Millisecond).cmd()
+	assert.Equal(t, "PSETEX", command)
+	assert.Equal(t, []any{"k", int64(250), []byte("v")}, args)
+}
@@ -0,0 +1,351 @@
+package redis
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotExecuted is the error of a Future whose pipeline has not been
+	// executed yet.
+	ErrNotExecuted = errors.New("redis: pipeline not executed")
+	// ErrTxFailed is returned by TxPipelined when a watched key was modified
+	// before the transaction ran, so none of its commands were executed.
+	ErrTxFailed = errors.New("redis: transaction aborted because a watched key changed")
+)
+
+// Future is the result of a command queued in a Pipe. It is available once the
+// pipe has been executed.
+type Future[T any] struct {
+	command string
+	args    []any
+	decoder func(string, []*Result) (T, error)
+	val     T
+	err     error
+}
+
+func newFuture[T any](command string, args []any, decoder func(string, []*Result) (T, error)) *Future[T] {
+	return &Future[T]{command: command, args: args, decoder: decoder, err: ErrNotExecuted}
+}
+
+// Val returns the result of the command.
+func (f *Future[T]) Val() (T, error) {
+	return f.val, f.err
+}
+
+// Err returns the error of the command, if any.
+func (f *Future[T]) Err() error {
+	return f.err
+}
+
+func (f *Future[T]) cmd() (string, []any) {
+	return f.command, f.args
+}
+
+func (f *Future[T]) set(results []*Result, err error) {
+	f.val, f.err = decode(f.command, f.decoder)(results, err)
+}
+
+func (f *Future[T]) error() error {
+	return f.err
+}
+
+type queued interface {
+	cmd() (string, []any)
+	set(results []*Result, err error)
+	error() error
+}
+
+// Pipe queues commands to be executed together, returning a Future for the
+// result of each.
+type Pipe interface {
+	// Do queues an arbitrary command, as with Client.Execute.
+	Do(command string, args ...any) *Future[[]*Result]
+	Get(key string) *Future[[]byte]
+	Set(key string, value []byte) *Future[struct{}]
+	SetEx(key string, value []byte, ttl time.Duration) *Future[struct{}]
+	SetNX(key string, value []byte) *Future[bool]
+	Incr(key string) *Future[int64]
+	IncrBy(key string, n int64) *Future[int64]
+	DecrBy(key string, n int64) *Future[int64]
+	Del(keys ...string) *Future[int64]
+	Expire(key string, ttl time.Duration) *Future[bool]
+	HSet(key string, fields map[string][]byte) *Future[int64]
+	HGet(key, field string) *Future[[]byte]
+	HIncrBy(key, field string, n int64) *Future[int64]
+	LPush(key string, values ...[]byte) *Future[int64]
+	RPop(key string) *Future[[]byte]
+	ZAdd(key string, members ...Z) *Future[int64]
+}
+
+// queue implements Pipe by recording commands.
+type queue struct {
+	cmds []queued
+}
+
+func enqueue[T any](q *queue, command string, args []any, decoder func(string, []*Result) (T, error)) *Future[T] {
+	f := newFuture(command, args, decoder)
+	q.cmds = append(q.cmds, f)
+	return f
+}
+
+func (q *queue) Do(command string, args ...any) *Future[[]*Result] {
+	return enqueue(q, command, args, func(_ string, results []*Result) ([]*Result, error) {
+		return results, nil
+	})
+}
+
+func (q *queue) Get(key string) *Future[[]byte] {
+	return enqueue(q, "GET", []any{key}, decodeOptionalBinary)
+}
+
+func (q *queue) Set(key string, value []byte) *Future[struct{}] {
+	return enqueue(q, "SET", []any{key, value}, decodeOK)
+}
+
+func (q *queue) SetEx(key string, value []byte, ttl time.Duration) *Future[struct{}] {
+	return enqueue(q, "PSETEX", []any{key, ttl.Milliseconds(), value}, decodeOK)
+}
+
+func (q *queue) SetNX(key string, value []byte) *Future[bool] {
+	return enqueue(q, "SETNX", []any{key, value}, decodeBool)
+}
+
+func (q *queue) Incr(key string) *Future[int64] {
+	return enqueue(q, "INCR", []any{key}, decodeInt)
+}
+
+func (q *queue) IncrBy(key string, n int64) *Future[int64] {
+	return enqueue(q, "INCRBY", []any{key, n}, decodeInt)
+}
+
+func (q *queue) DecrBy(key string, n int64) *Future[int64] {
+	return enqueue(q, "DECRBY", []any{key, n}, decodeInt)
+}
+
+func (q *queue) Del(keys ...string) *Future[int64] {
+	return enqueue(q, "DEL", toAnys(keys), decodeInt)
+}
+
+func (q *queue) Expire(key string, ttl time.Duration) *Future[bool] {
+	return enqueue(q, "PEXPIRE", []any{key, ttl.Milliseconds()}, decodeBool)
+}
+
+func (q *queue) HSet(key string, fields map[string][]byte) *Future[int64] {
+	return enqueue(q, "HSET", hsetArgs(key, fields), decodeInt)
+}
+
+func (q *queue) HGet(key, field string) *Future[[]byte] {
+	return enqueue(q, "HGET", []any{key, field}, decodeOptionalBinary)
+}
+
+func (q *queue) HIncrBy(key, field string, n int64) *Future[int64] {
+	return enqueue(q, "HINCRBY", []any{key, field, n}, decodeInt)
+}
+
+func (q *queue) LPush(key string, values ...[]byte) *Future[int64] {
+	return enqueue(q, "LPUSH", lpushArgs(key, values), decodeInt)
+}
+
+func (q *queue) RPop(key string) *Future[[]byte] {
+	return enqueue(q, "RPOP", []any{key}, decodeOptionalBinary)
+}
+
+func (q *queue) ZAdd(key string, members ...Z) *Future[int64] {
+	return enqueue(q, "ZADD", zaddArgs(key, members), decodeInt)
+}
+
+var _ Pipe = (*queue)(nil)
+
+// Pipeline queues commands and executes them concurrently, saving a host
+// round trip per command. The commands are independent and may run in any
+// order; use TxPipelined for commands that must run in order and atomically.
+type Pipeline struct {
+	queue
+	client *Client
+}
+
+// Pipeline returns a new, empty pipeline.
+func (c *Client) Pipeline() *Pipeline {
+	return &Pipeline{client: c}
+}
+
+// Exec executes the queued commands, sets their futures, and empties the
+// pipeline. It returns the first error among the commands, in the order they
+// were queued.
+func (p *Pipeline) Exec() error {
+	cmds := p.cmds
+	p.cmds = nil
+
+	var wg sync.WaitGroup
+	for _, cmd := range cmds {
+		wg.Go(func() {
+			command, args := cmd.cmd()
+			cmd.set(p.client.Execute(command, args...))
+		})
+	}
+	wg.Wait()
+
+	return firstError(cmds)
+}
+
+// Pipelined queues the commands issued by fn in a pipeline and executes them.
+func (c *Client) Pipelined(fn func(Pipe) error) error {
+	p := c.Pipeline()
+	if err := fn(p); err != nil {
+		return err
+	}
+	return p.Exec()
+}
+
+// TxPipelined executes the commands queued by fn atomically, in a MULTI/EXEC
+// transaction.
+//
+// If keys are given, they are watched before fn is called: fn may read them
+// with the client and decide what to queue, and the transaction is aborted
+// with ErrTxFailed if any of them is modified before it runs. Callers
+// typically retry on ErrTxFailed:
+//
+//	err := client.TxPipelined(func(pipe redis.Pipe) error {
+//		stock, err := client.Get("stock:42")
+//		if err != nil {
+//			return err
+//		}
+//		if n, _ := strconv.Atoi(string(stock)); n < 1 {
+//			return errSoldOut
+//		}
+//		pipe.DecrBy("stock:42", 1)
+//		pipe.LPush("reservations:42", []byte(orderID))
+//		return nil
+//	}, "stock:42")
+//
+// If fn returns an error, nothing is executed and the error is returned.
+// Watches and transactions are per connection, so the client must not be
+// used concurrently until TxPipelined returns.
+func (c *Client) TxPipelined(fn func(Pipe) error, keys ...string) error {
+	if len(keys) > 0 {
+		if err := c.okCommand("WATCH", toAnys(keys)...); err != nil {
+			return err
+		}
+	}
+
+	var q queue
+	if err := fn(&q); err != nil {
+		c.unwatch(keys)
+		return err
+	}
+	cmds := q.cmds
+	if len(cmds) == 0 {
+		c.unwatch(keys)
+		return nil
+	}
+
+	fail := func(err error) error {
+		for _, cmd := range cmds {
+			cmd.set(nil, err)
+		}
+		return err
+	}
+
+	if err := c.okCommand("MULTI"); err != nil {
+		c.unwatch(keys)
+		return fail(err)
+	}
+
+	// The host flattens the replies of EXEC into a single list and omits nil
+	// and "OK" replies, so each command is followed by an ECHO of a random
+	// token to mark where its replies end. An empty reply means that EXEC was
+	// aborted.
+	token := newToken()
+	for _, cmd := range cmds {
+		command, args := cmd.cmd()
+		if err := c.queueCommand(command, args...); err != nil {
+			c.Execute("DISCARD")
+			return fail(err)
+		}
+		if err := c.queueCommand("ECHO", token); err != nil {
+			c.Execute("DISCARD")
+			return fail(err)
+		}
+	}
+
+	results, err := c.Execute("EXEC")
+	if err != nil {
+		return fail(err)
+	}
+	replies, err := splitReplies(results, token, len(cmds))
+	if err != nil {
+		return fail(err)
+	}
+
+	for i, cmd := range cmds {
+		cmd.set(replies[i], nil)
+	}
+	return firstError(cmds)
+}
+
+// splitReplies splits the flattened replies of EXEC into the replies of each
+// of n commands, which were each followed by an ECHO of token.
+func splitReplies(results []*Result, token string, n int) ([][]*Result, error) {
+	if len(results) == 0 {
+		return nil, ErrTxFailed
+	}
+	var replies [][]*Result
+	start := 0
+	for i, r := range results {
+		if r.Kind == ResultKindBinary && bytes.Equal(r.Val.([]byte), []byte(token)) {
+			replies = append(replies, results[start:i])
+			start = i + 1
+		}
+	}
+	if len(replies) != n || start != len(results) {
+		return nil, fmt.Errorf("redis: EXEC returned %d results that do not match the %d queued commands", len(results), n)
+	}
+	return replies, nil
+}
+
+// queueCommand sends a command within MULTI, which the server queues.
+func (c *Client) queueCommand(command string, args ...any) error {
+	results, err := c.Execute(command, args...)
+	if err != nil {
+		return err
+	}
+	if len(results) != 1 || results[0].Kind != ResultKindStatus || results[0].Val != "QUEUED" {
+		return fmt.Errorf("redis: %s was not queued", command)
+	}
+	return nil
+}
+
+func (c *Client) unwatch(keys []string) {
+	if len(keys) > 0 {
+		c.okCommand("UNWATCH")
+	}
+}
+
+func firstError(cmds []queued) error {
+	for _, cmd := range cmds {
+		if err := cmd.error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func toAnys(strs []string) []any {
+	args := make([]any, len(strs))
+	for i, s := range strs {
+		args[i] = s
+	}
+	return args
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitReplies(t *testing.T) {
+	const token = "0123456789abcdef"
+	echo := binary(token)
+
+	tests := []struct {
+		name    string
+		results []*Result
+		n       int
+		want    [][]*Result
+		wantErr string
+	}{
+		{
+			name:    "one reply per command",
+			results: []*Result{integer(1), echo, binary("v"), echo},
+			n:       2,
+			want:    [][]*Result{{integer(1)}, {binary("v")}},
+		},
+		{
+			name:    "OK and nil replies omitted",
+			results: []*Result{echo, echo, integer(2), echo},
+			n:       3,
+			want:    [][]*Result{{}, {}, {integer(2)}},
+		},
+		{
+			name:    "flattened array reply",
+			results: []*Result{binary("a"), binary("b"), echo},
+			n:       1,
+			want:    [][]*Result{{binary("a"), binary("b")}},
+		},
+		{
+			name:    "aborted EXEC",
+			results: nil,
+			n:       2,
+			wantErr: ErrTxFailed.Error(),
+		},
+		{
+			name:    "fewer tokens than commands",
+			results: []*Result{integer(1), echo},
+			n:       2,
+			wantErr: "redis: EXEC returned 2 results that do not match the 2 queued commands",
+		},
+		{
+			name:    "replies after the last token",
+			results: []*Result{integer(1), echo, integer(2)},
+			n:       1,
+			wantErr: "redis: EXEC returned 3 results that do not match the 1 queued commands",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies, err := splitReplies(tt.results, token, tt.n)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, replies, len(tt.want))
+			for i := range tt.want {
+				assert.ElementsMatch(t, tt.want[i], replies[i])
+			}
+		})
+	}
+}
@@ -1,28 +1,33 @@
 // Package redis provides access to Redis within Spin components, as well as a
 // handler for inbound Redis messages.
+//
+// By default, Handle registers the handler with the spin:up/redis-trigger@4.0.0
+// world. To target the legacy fermyon:spin/redis-trigger world supported by
+// older Spin hosts, build with the fermyon_spin_redis_trigger tag:
+//
+//	GOFLAGS=-tags=fermyon_spin_redis_trigger go tool componentize-go --world fermyon:spin/redis-trigger build
+//
+// Payloads, and errors and panics in the handler, are translated the same way
+// for both worlds, except that the legacy world cannot report error messages
+// to Spin; they are written to standard error instead.
+//
+// The legacy world does not import the spin:redis/redis@3.0.0 interface that
+// Client, and so Router's dead-letter channel, are built on. Components that
+// use them must also be built with the spin:up/platform@4.0.0 world, which
+// adds the imports without any exports, and run on a Spin host that provides
+// them:
+//
+//	GOFLAGS=-tags=fermyon_spin_redis_trigger go tool componentize-go -w fermyon:spin/redis-trigger -w spin:up/platform@4.0.0 build
 package redis
 
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
-	incominghandler "github.com/spinframework/spin-go-sdk/v3/exports/spin_up_redis_trigger_4_0_0/export_spin_redis_3_0_0_inbound_redis"
-	_ "github.com/spinframework/spin-go-sdk/v3/exports/spin_up_redis_trigger_4_0_0/wit_exports"
 	redis "github.com/spinframework/spin-go-sdk/v3/imports/spin_redis_3_0_0_redis"
-	wit "go.bytecodealliance.org/pkg/wit/types"
 )
 
-// Handle sets the handler function for the inbound Redis trigger.
-// It must be called from an init() function.
-func Handle(handle func(message []byte) error) {
-	incominghandler.Exports.Handle = func(message []byte) wit.Result[wit.Unit, redis.Error] {
-		if err := handle(message); err != nil {
-			return wit.Err[wit.Unit](redis.MakeErrorOther(err.Error()))
-		}
-		return wit.Ok[wit.Unit, redis.Error](wit.Unit{})
-	}
-}
-
 // Client is a Redis client.
 type Client struct {
 	conn redis.Connection
@@ -173,7 +178,8 @@ type Result struct {
 // returning zero or more results.  This is a general-purpose function which
 // should work with any Redis command.
 //
-// Arguments must be string, []byte, int, int64, or int32.
+// Arguments must be string, []byte, int, int64, int32, uint32, float64 or
+// bool. Floats are sent in their shortest decimal form, and bools as 1 or 0.
 func (c *Client) Execute(command string, arguments ...any) ([]*Result, error) {
 	var params []redis.RedisParameter
 	for _, a := range arguments {
@@ -205,12 +211,21 @@ func createParameter(x any) (redis.RedisParameter, error) {
 		return redis.MakeRedisParameterInt64(v), nil
 	case int32:
 		return redis.MakeRedisParameterInt64(int64(v)), nil
+	case uint32:
+		return redis.MakeRedisParameterInt64(int64(v)), nil
+	case float64:
+		return redis.MakeRedisParameterBinary(redis.Payload(strconv.FormatFloat(v, 'g', -1, 64))), nil
+	case bool:
+		if v {
+			return redis.MakeRedisParameterInt64(1), nil
+		}
+		return redis.MakeRedisParameterInt64(0), nil
 	case []byte:
 		return redis.MakeRedisParameterBinary(redis.Payload(v)), nil
 	case string:
 		return redis.MakeRedisParameterBinary(redis.Payload(v)), nil
 	default:
-		return redis.RedisParameter{}, fmt.Errorf("invalid type %T; must be string, []byte, int, int64, or int32", v)
+		return redis.RedisParameter{}, fmt.Errorf("invalid type %T; must be string, []byte, int, int64, int32, uint32, float64, or bool", v)
 	}
 }
 
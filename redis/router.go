@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spinframework/spin-go-sdk/v3/internal/redistrigger"
+)
+
+// ErrNoRoute is returned by Router.Handle for a message that matches no
+// route.
+var ErrNoRoute = errors.New("redis: no route for message")
+
+// HandlerFunc handles an inbound Redis message.
+type HandlerFunc func(message []byte) error
+
+// Middleware wraps a HandlerFunc, for example to log or to validate messages.
+type Middleware func(HandlerFunc) HandlerFunc
+
+// Router routes inbound Redis messages to handlers by their content. Install
+// it with Handle:
+//
+//	func init() {
+//		router := redis.NewRouter()
+//		router.HandleType("order.created", redis.JSONHandler(func(o OrderCreated) error {
+//			...
+//		}))
+//		router.HandlePrefix("ping:", func(rest []byte) error { ... })
+//		router.SetDeadLetter("redis://localhost:6379", "dead-letters")
+//		redis.Handle(router.Handle)
+//	}
+//
+// A message is routed by the "type" field of a JSON object, if there is a
+// route for it, and otherwise by the longest matching prefix. Messages that
+// match no route go to the default handler, if any.
+//
+// The Spin trigger does not report which channel a message was published to,
+// so routes cannot depend on the channel.
+type Router struct {
+	types      map[string]HandlerFunc
+	prefixes   []prefixRoute
+	fallback   HandlerFunc
+	middleware []Middleware
+
+	// deadLetter publishes a JSON-encoded DeadLetterMessage, if set.
+	deadLetter func(payload []byte) error
+}
+
+type prefixRoute struct {
+	prefix  string
+	handler HandlerFunc
+}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router {
+	return &Router{types: map[string]HandlerFunc{}}
+}
+
+// HandleType routes JSON objects whose "type" field is typ to h, which
+// receives the whole message.
+func (r *Router) HandleType(typ string, h HandlerFunc) {
+	r.types[typ] = h
+}
+
+// HandlePrefix routes messages that start with prefix to h, which receives the
+// rest of the message.
+func (r *Router) HandlePrefix(prefix string, h HandlerFunc) {
+	r.prefixes = append(r.prefixes, prefixRoute{prefix: prefix, handler: h})
+	slices.SortStableFunc(r.prefixes, func(a, b prefixRoute) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+}
+
+// HandleDefault sets the handler for messages that match no route.
+func (r *Router) HandleDefault(h HandlerFunc) {
+	r.fallback = h
+}
+
+// Use adds middleware that wraps every handler. Middleware added first is
+// outermost.
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// SetDeadLetter publishes messages that fail, because their handler returned
+// an error or panicked or because they matched no route, to channel on the
+// Redis server at address. They are published as JSON-encoded
+// DeadLetterMessages. The connection is opened on the first failure. With the
+// legacy redis trigger, this needs the imports described in the package
+// documentation.
+func (r *Router) SetDeadLetter(address, channel string) {
+	var (
+		once   sync.Once
+		client Client
+		err    error
+	)
+	r.deadLetter = func(payload []byte) error {
+		once.Do(func() { client, err = NewClient(address) })
+		if err != nil {
+			return err
+		}
+		return client.Publish(channel, payload)
+	}
+}
+
+// DeadLetterMessage is published to the dead-letter channel of a Router for
+// each message that fails.
+type DeadLetterMessage struct {
+	// Error is the error that the message failed with.
+	Error string `json:"error"`
+	// Payload is the original message.
+	Payload []byte `json:"payload"`
+}
+
+// Handle routes a message to its handler and returns the handler's error. A
+// panic in the handler is recovered and returned as an error that carries the
+// panic value and stack trace.
+func (r *Router) Handle(message []byte) error {
+	h, payload := r.route(message)
+
+	for _, mw := range slices.Backward(r.middleware) {
+		h = mw(h)
+	}
+
+	err := redistrigger.Call(h, payload)
+	if err != nil && r.deadLetter != nil {
+		if dlErr := r.publishDeadLetter(message, err); dlErr != nil {
+			err = errors.Join(err, fmt.Errorf("redis: publishing to dead-letter channel: %w", dlErr))
+		}
+	}
+	return err
+}
+
+// route returns the handler for a message and the payload to pass to it.
+func (r *Router) route(message []byte) (HandlerFunc, []byte) {
+	if len(r.types) > 0 {
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(message, &envelope) == nil {
+			if h, ok := r.types[envelope.Type]; ok {
+				return h, message
+			}
+		}
+	}
+
+	for _, route := range r.prefixes {
+		if rest, ok := strings.CutPrefix(string(message), route.prefix); ok {
+			return route.handler, []byte(rest)
+		}
+	}
+
+	if r.fallback != nil {
+		return r.fallback, message
+	}
+	return func([]byte) error { return ErrNoRoute }, message
+}
+
+func (r *Router) publishDeadLetter(message []byte, failure error) error {
+	payload, err := json.Marshal(DeadLetterMessage{Error: failure.Error(), Payload: message})
+	if err != nil {
+		return err
+	}
+	return r.deadLetter(payload)
+}
+
+// JSONHandler returns a HandlerFunc that decodes messages as JSON into a T
+// and passes them to fn. Messages that cannot be decoded fail with the
+// decoding error.
+func JSONHandler[T any](fn func(T) error) HandlerFunc {
+	return func(message []byte) error {
+		var v T
+		if err := json.Unmarshal(message, &v); err != nil {
+			return fmt.Errorf("redis: decoding message: %w", err)
+		}
+		return fn(v)
+	}
+}
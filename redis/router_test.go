@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterRoute(t *testing.T) {
+	var got string
+	route := func(name string) HandlerFunc {
+		return func(message []byte) error {
+			got = name + " " + string(message)
+			return nil
+		}
+	}
+
+	router := NewRouter()
+	router.HandleType("order.created", route("created"))
+	router.HandlePrefix("ping:", route("ping"))
+	router.HandlePrefix("ping:urgent:", route("urgent"))
+	router.HandlePrefix("{", route("brace"))
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{`{"type":"order.created","id":1}`, `created {"type":"order.created","id":1}`},
+		{`{"type":"order.deleted"}`, `brace "type":"order.deleted"}`},
+		{`{"id":1}`, `brace "id":1}`},
+		{`{"type":`, `brace "type":`},
+		{"ping:hello", "ping hello"},
+		{"ping:urgent:hello", "urgent hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			got = ""
+			require.NoError(t, router.Handle([]byte(tt.message)))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("no route", func(t *testing.T) {
+		assert.ErrorIs(t, router.Handle([]byte("pong")), ErrNoRoute)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		router.HandleDefault(route("default"))
+		got = ""
+		require.NoError(t, router.Handle([]byte("pong")))
+		assert.Equal(t, "default pong", got)
+	})
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	var calls []string
+	mw := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(message []byte) error {
+				calls = append(calls, name)
+				return next(message)
+			}
+		}
+	}
+
+	router := NewRouter()
+	router.Use(mw("outer"), mw("inner"))
+	router.HandleDefault(func([]byte) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	require.NoError(t, router.Handle([]byte("hello")))
+	assert.Equal(t, []string{"outer", "inner", "handler"}, calls)
+}
+
+func TestRouterPanic(t *testing.T) {
+	router := NewRouter()
+	router.HandleDefault(func([]byte) error { panic("kaboom") })
+
+	err := router.Handle([]byte("hello"))
+	require.Error(t, err)
+	first, stack, _ := strings.Cut(err.Error(), "\n")
+	assert.Equal(t, "redis: handler panicked: kaboom", first)
+	assert.Contains(t, stack, "runtime/debug.Stack")
+}
+
+func TestRouterDeadLetter(t *testing.T) {
+	// SetDeadLetter would connect to Redis, so publish to a fake instead.
+	newRouter := func(publish func([]byte) error) *Router {
+		router := NewRouter()
+		router.deadLetter = publish
+		router.HandlePrefix("ok:", func([]byte) error { return nil })
+		router.HandlePrefix("fail:", func([]byte) error { return errors.New("boom") })
+		return router
+	}
+
+	var got []DeadLetterMessage
+	router := newRouter(func(payload []byte) error {
+		var message DeadLetterMessage
+		require.NoError(t, json.Unmarshal(payload, &message))
+		got = append(got, message)
+		return nil
+	})
+
+	require.NoError(t, router.Handle([]byte("ok:1")))
+	assert.Empty(t, got)
+
+	assert.EqualError(t, router.Handle([]byte("fail:2")), "boom")
+	assert.ErrorIs(t, router.Handle([]byte("other")), ErrNoRoute)
+	assert.Equal(t, []DeadLetterMessage{
+		{Error: "boom", Payload: []byte("fail:2")},
+		{Error: ErrNoRoute.Error(), Payload: []byte("other")},
+	}, got)
+
+	t.Run("publishing fails", func(t *testing.T) {
+		router := newRouter(func([]byte) error { return errors.New("redis: too many connections") })
+		err := router.Handle([]byte("fail:3"))
+		assert.EqualError(t, err, "boom\nredis: publishing to dead-letter channel: redis: too many connections")
+	})
+}
+
+func TestJSONHandler(t *testing.T) {
+	type order struct {
+		Type string `json:"type"`
+		ID   int    `json:"id"`
+	}
+
+	var got order
+	h := JSONHandler(func(o order) error {
+		got = o
+		return nil
+	})
+
+	require.NoError(t, h([]byte(`{"type":"order.created","id":7}`)))
+	assert.Equal(t, order{Type: "order.created", ID: 7}, got)
+
+	err := h([]byte(`{"id":"seven"}`))
+	assert.ErrorContains(t, err, "redis: decoding message: ")
+	var typeErr *json.UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr))
+
+	t.Run("errors are returned", func(t *testing.T) {
+		h := JSONHandler(func(order) error { return errors.New("boom") })
+		assert.EqualError(t, h([]byte(`{}`)), "boom")
+	})
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Script is a Lua script run with EVALSHA, so that the server caches it and
+// the source is only sent when needed.
+//
+//	var reserve = redis.NewScript(`
+//		local stock = tonumber(redis.call("GET", KEYS[1]) or "0")
+//		if stock < tonumber(ARGV[1]) then return 0 end
+//		redis.call("DECRBY", KEYS[1], ARGV[1])
+//		return 1
+//	`)
+//
+//	res, err := reserve.Run(&client, []string{"stock:42"}, 2)
+//	// if err != nil { ... }
+//	ok, err := res.Bool()
+//
+// Scripts are safe to share between goroutines and requests.
+type Script struct {
+	src  string
+	hash string
+}
+
+// NewScript returns a Script for the Lua source src.
+func NewScript(src string) *Script {
+	sum := sha1.Sum([]byte(src))
+	return &Script{src: src, hash: hex.EncodeToString(sum[:])}
+}
+
+// Hash returns the SHA1 digest of the script, as used by EVALSHA.
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Load loads the script into the server's script cache.
+func (s *Script) Load(c *Client) error {
+	hash, err := c.optionalBinaryCommand("SCRIPT", "LOAD", s.src)
+	if err != nil {
+		return err
+	}
+	if string(hash) != s.hash {
+		return fmt.Errorf("redis: SCRIPT LOAD returned hash %q, want %q", hash, s.hash)
+	}
+	return nil
+}
+
+// Exists reports whether the script is in the server's script cache.
+func (s *Script) Exists(c *Client) (bool, error) {
+	results, err := c.Execute("SCRIPT", "EXISTS", s.hash)
+	if err != nil {
+		return false, err
+	}
+	return decodeBool("SCRIPT EXISTS", results)
+}
+
+// Run runs the script with EVALSHA, or with EVAL if the server does not have
+// the script cached, which also caches it. Keys are passed in KEYS, and args
+// in ARGV; args must be of the types accepted by Client.Execute.
+func (s *Script) Run(c *Client, keys []string, args ...any) (*ScriptResult, error) {
+	return s.run(c.Execute, keys, args)
+}
+
+// run implements Run with execute in place of Client.Execute.
+func (s *Script) run(execute func(command string, args ...any) ([]*Result, error), keys []string, args []any) (*ScriptResult, error) {
+	results, err := execute("EVALSHA", s.evalArgs(s.hash, keys, args)...)
+	if err != nil && isNoScript(err) {
+		results, err = execute("EVAL", s.evalArgs(s.src, keys, args)...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ScriptResult{results: results}, nil
+}
+
+func (s *Script) evalArgs(script string, keys []string, args []any) []any {
+	evalArgs := make([]any, 0, 2+len(keys)+len(args))
+	evalArgs = append(evalArgs, script, len(keys))
+	evalArgs = append(evalArgs, toAnys(keys)...)
+	return append(evalArgs, args...)
+}
+
+// isNoScript reports whether err is the server's NOSCRIPT error, returned by
+// EVALSHA when the script is not cached.
+func isNoScript(err error) bool {
+	return strings.Contains(err.Error(), "NOSCRIPT")
+}
+
+// ScriptResult is the value returned by a script.
+//
+// Lua numbers are returned as integers, with any fractional part truncated,
+// and strings as binary results. The Spin host flattens tables into a single
+// list of results and omits nil and false values, which are therefore
+// indistinguishable from an empty table. Return numbers as strings with
+// tostring to preserve fractions.
+type ScriptResult struct {
+	results []*Result
+}
+
+// Results returns the raw results.
+func (r *ScriptResult) Results() []*Result {
+	return r.results
+}
+
+// Int returns the result as an integer.
+func (r *ScriptResult) Int() (int64, error) {
+	return decodeInt("EVALSHA", r.results)
+}
+
+// Bool returns whether the result is the integer 1, which is what Lua's true
+// is converted to.
+func (r *ScriptResult) Bool() (bool, error) {
+	if len(r.results) == 0 {
+		// Lua's false is converted to nil, which the host omits.
+		return false, nil
+	}
+	return decodeBool("EVALSHA", r.results)
+}
+
+// Bytes returns the result as a byte slice, or nil if the script returned
+// nil.
+func (r *ScriptResult) Bytes() ([]byte, error) {
+	return decodeOptionalBinary("EVALSHA", r.results)
+}
+
+// BytesSlice returns a result that is a table of strings.
+func (r *ScriptResult) BytesSlice() ([][]byte, error) {
+	return decodeBinaries("EVALSHA", r.results)
+}
+
+// Strings returns a result that is a table of strings.
+func (r *ScriptResult) Strings() ([]string, error) {
+	values, err := decodeBinaries("EVALSHA", r.results)
+	if err != nil {
+		return nil, err
+	}
+	return toStrings(values), nil
+}
+
+// Values returns the result as Go values: int64 for integers, []byte for
+// strings and string for status replies.
+func (r *ScriptResult) Values() []any {
+	values := make([]any, len(r.results))
+	for i, result := range r.results {
+		values[i] = result.Val
+	}
+	return values
+}
@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalArgs(t *testing.T) {
+	s := NewScript("return 1")
+
+	tests := []struct {
+		name string
+		keys []string
+		args []any
+		want []any
+	}{
+		{"no keys or args", nil, nil, []any{s.Hash(), 0}},
+		{"keys only", []string{"a", "b"}, nil, []any{s.Hash(), 2, "a", "b"}},
+		{"args only", nil, []any{1, "x"}, []any{s.Hash(), 0, 1, "x"}},
+		{"keys then args", []string{"stock:42"}, []any{2, []byte("order")}, []any{s.Hash(), 1, "stock:42", 2, []byte("order")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, s.evalArgs(s.Hash(), tt.keys, tt.args))
+		})
+	}
+}
+
+func TestIsNoScript(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("redis: NOSCRIPT No matching script. Please use EVAL."), true},
+		{errors.New("redis: ERR Error running script"), false},
+		{errors.New("redis: type error"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.want, isNoScript(tt.err))
+		})
+	}
+}
+
+func TestScriptResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []*Result
+		bool    bool
+		boolErr string
+		int     int64
+		intErr  string
+		strings []string
+		strErr  string
+	}{{
+		name:    "true",
+		results: []*Result{integer(1)},
+		bool:    true,
+		int:     1,
+		strErr:  "redis: EVALSHA returned a int64 result, want binary",
+	}, {
+		name:    "zero",
+		results: []*Result{integer(0)},
+		int:     0,
+		strErr:  "redis: EVALSHA returned a int64 result, want binary",
+	}, {
+		// Lua's false and nil are omitted by the host.
+		name:    "false or nil",
+		results: nil,
+		intErr:  "redis: EVALSHA returned 0 results, want 1",
+		strings: []string{},
+	}, {
+		name:    "flattened table",
+		results: []*Result{binary("a"), binary("b")},
+		boolErr: "redis: EVALSHA returned 2 results, want 1",
+		intErr:  "redis: EVALSHA returned 2 results, want 1",
+		strings: []string{"a", "b"},
+	}, {
+		name:    "string",
+		results: []*Result{binary("42")},
+		boolErr: "redis: EVALSHA returned a binary result, want int64",
+		intErr:  "redis: EVALSHA returned a binary result, want int64",
+		strings: []string{"42"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ScriptResult{results: tt.results}
+
+			b, err := r.Bool()
+			if tt.boolErr != "" {
+				assert.EqualError(t, err, tt.boolErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.bool, b)
+			}
+
+			n, err := r.Int()
+			if tt.intErr != "" {
+				assert.EqualError(t, err, tt.intErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.int, n)
+			}
+
+			strs, err := r.Strings()
+			if tt.strErr != "" {
+				assert.EqualError(t, err, tt.strErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.strings, strs)
+			}
+		})
+	}
+}
+
+func TestScriptRun(t *testing.T) {
+	s := NewScript("return redis.call('GET', KEYS[1])")
+
+	type call struct {
+		command string
+		args    []any
+	}
+
+	tests := []struct {
+		name    string
+		errs    map[string]error
+		want    []call
+		wantErr string
+	}{{
+		name: "cached",
+		want: []call{{"EVALSHA", []any{s.Hash(), 1, "k", "v"}}},
+	}, {
+		name: "falls back to EVAL",
+		errs: map[string]error{"EVALSHA": errors.New("redis: NOSCRIPT No matching script. Please use EVAL.")},
+		want: []call{
+			{"EVALSHA", []any{s.Hash(), 1, "k", "v"}},
+			{"EVAL", []any{s.src, 1, "k", "v"}},
+		},
+	}, {
+		name:    "other errors are returned",
+		errs:    map[string]error{"EVALSHA": errors.New("redis: ERR Error running script")},
+		want:    []call{{"EVALSHA", []any{s.Hash(), 1, "k", "v"}}},
+		wantErr: "redis: ERR Error running script",
+	}, {
+		name: "EVAL fails",
+		errs: map[string]error{
+			"EVALSHA": errors.New("redis: NOSCRIPT No matching script. Please use EVAL."),
+			"EVAL":    errors.New("redis: ERR Error compiling script"),
+		},
+		want: []call{
+			{"EVALSHA", []any{s.Hash(), 1, "k", "v"}},
+			{"EVAL", []any{s.src, 1, "k", "v"}},
+		},
+		wantErr: "redis: ERR Error compiling script",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []call
+			execute := func(command string, args ...any) ([]*Result, error) {
+				calls = append(calls, call{command, args})
+				if err := tt.errs[command]; err != nil {
+					return nil, err
+				}
+				return []*Result{binary("value")}, nil
+			}
+
+			res, err := s.run(execute, []string{"k"}, []any{"v"})
+			assert.Equal(t, tt.want, calls)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			b, err := res.Bytes()
+			require.NoError(t, err)
+			assert.Equal(t, []byte("value"), b)
+		})
+	}
+}
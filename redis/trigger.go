@@ -0,0 +1,17 @@
+//go:build !fermyon_spin_redis_trigger
+
+package redis
+
+import (
+	incominghandler "github.com/spinframework/spin-go-sdk/v3/exports/spin_up_redis_trigger_4_0_0/export_spin_redis_3_0_0_inbound_redis"
+	_ "github.com/spinframework/spin-go-sdk/v3/exports/spin_up_redis_trigger_4_0_0/wit_exports"
+	"github.com/spinframework/spin-go-sdk/v3/internal/redistrigger"
+)
+
+// Handle sets the handler function for the inbound Redis trigger.
+// It must be called from an init() function. Errors returned by the handler,
+// and panics with their stack trace, are reported to Spin as "other" errors.
+// See Router for routing messages to several handlers.
+func Handle(handle func(message []byte) error) {
+	incominghandler.Exports.Handle = redistrigger.Handler(handle)
+}
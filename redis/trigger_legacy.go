@@ -0,0 +1,21 @@
+//go:build fermyon_spin_redis_trigger
+
+package redis
+
+import (
+	"os"
+
+	incominghandler "github.com/spinframework/spin-go-sdk/v3/exports/fermyon_spin_redis_trigger/export_fermyon_spin_inbound_redis"
+	_ "github.com/spinframework/spin-go-sdk/v3/exports/fermyon_spin_redis_trigger/wit_exports"
+	"github.com/spinframework/spin-go-sdk/v3/internal/redistrigger"
+)
+
+// Handle sets the handler function for the inbound Redis trigger of the
+// legacy fermyon:spin/redis-trigger world. It must be called from an init()
+// function. Errors returned by the handler, and panics with their stack
+// trace, are written to standard error and reported to Spin as a failure.
+// See Router for routing messages to several handlers, and the package
+// documentation for building a component that also uses Client.
+func Handle(handle func(message []byte) error) {
+	incominghandler.Exports.Handle = redistrigger.LegacyHandler(handle, os.Stderr)
+}
@@ -45,6 +45,9 @@ do
     --pkg-name github.com/spinframework/spin-go-sdk/v3/imports \
     --include-versions
   cp -r tmp/wit_exports $dir/
+  # Let the exports build without WebAssembly too, as the imports do, so that
+  # the packages using them can be tested on the host.
+  cp imports/spin_redis_3_0_0_redis/empty.s $dir/wit_exports/
   rm -rf tmp
 done
 
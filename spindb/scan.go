@@ -0,0 +1,254 @@
+package spindb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+	mapType     = reflect.TypeFor[map[string]any]()
+)
+
+// scanner scans rows into values of type T. It is built once per query from
+// the columns of the result.
+type scanner[T any] struct {
+	typ     reflect.Type
+	columns []string
+	// types are the scan types reported by the driver for each column, used
+	// where the destination does not determine the type
+	types []reflect.Type
+	// fields are the index paths of the struct field for each column, if T
+	// is a struct or a pointer to one
+	fields [][]int
+}
+
+func newScanner[T any](rows *sql.Rows) (*scanner[T], error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &scanner[T]{
+		typ:     reflect.TypeFor[T](),
+		columns: columns,
+		types:   make([]reflect.Type, len(columns)),
+	}
+	for i, ct := range columnTypes {
+		s.types[i] = ct.ScanType()
+	}
+
+	if st, ok := structType(s.typ); ok {
+		fields := cachedFields(st)
+		s.fields = make([][]int, len(columns))
+		for i, column := range columns {
+			f, ok := fields.lookup(column)
+			if !ok {
+				return nil, fmt.Errorf("spindb: column %q has no matching field in %s", column, st)
+			}
+			s.fields[i] = f.index
+		}
+	} else if s.typ != mapType && len(columns) != 1 {
+		return nil, fmt.Errorf("spindb: cannot scan %d columns into %s", len(columns), s.typ)
+	}
+
+	return s, nil
+}
+
+// scan scans the current row.
+func (s *scanner[T]) scan(rows *sql.Rows) (T, error) {
+	var v T
+	dests := make([]any, len(s.columns))
+	var finish func()
+
+	switch {
+	case s.fields != nil:
+		rv := reflect.ValueOf(&v).Elem()
+		if rv.Kind() == reflect.Pointer {
+			rv.Set(reflect.New(rv.Type().Elem()))
+			rv = rv.Elem()
+		}
+		var holders []func()
+		for i, index := range s.fields {
+			field := rv.FieldByIndex(index)
+			if field.Kind() == reflect.Interface {
+				dest, value := s.holder(i)
+				dests[i] = dest
+				holders = append(holders, func() {
+					if x := value(); x != nil {
+						field.Set(reflect.ValueOf(x))
+					}
+				})
+				continue
+			}
+			dests[i] = field.Addr().Interface()
+		}
+		finish = func() {
+			for _, h := range holders {
+				h()
+			}
+		}
+	case s.typ == mapType:
+		values := make([]func() any, len(s.columns))
+		for i := range s.columns {
+			dests[i], values[i] = s.holder(i)
+		}
+		finish = func() {
+			m := make(map[string]any, len(s.columns))
+			for i, column := range s.columns {
+				m[column] = values[i]()
+			}
+			reflect.ValueOf(&v).Elem().Set(reflect.ValueOf(m))
+		}
+	default:
+		dests[0] = &v
+	}
+
+	if err := rows.Scan(dests...); err != nil {
+		return v, fmt.Errorf("spindb: %w", err)
+	}
+	if finish != nil {
+		finish()
+	}
+	return v, nil
+}
+
+// holder returns a scan destination for column i with the column's scan
+// type, and a function returning the scanned value, or nil for NULL.
+func (s *scanner[T]) holder(i int) (any, func() any) {
+	typ := s.types[i]
+	if typ == nil || typ.Kind() == reflect.Interface {
+		var x any
+		return &x, func() any { return x }
+	}
+
+	ptr := reflect.New(reflect.PointerTo(typ))
+	return ptr.Interface(), func() any {
+		if ptr.Elem().IsNil() {
+			return nil
+		}
+		return ptr.Elem().Elem().Interface()
+	}
+}
+
+// structType returns the struct type that rows are mapped to if typ is a
+// struct or a pointer to one. Structs that scan themselves, such as time.Time
+// or types implementing sql.Scanner, are scanned from a single column
+// instead.
+func structType(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType || reflect.PointerTo(typ).Implements(scannerType) {
+		return nil, false
+	}
+	return typ, true
+}
+
+type field struct {
+	name   string
+	tagged bool
+	index  []int
+}
+
+type fields []field
+
+// lookup returns the field for a column, preferring a field tagged with the
+// exact column name over one whose name matches ignoring case and
+// underscores, so that a field IsFinicky matches a column is_finicky.
+func (fs fields) lookup(column string) (field, bool) {
+	for _, f := range fs {
+		if f.tagged && f.name == column {
+			return f, true
+		}
+	}
+	key := foldName(column)
+	for _, f := range fs {
+		if foldName(f.name) == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+func foldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+var fieldCache sync.Map // map[reflect.Type]fields
+
+func cachedFields(typ reflect.Type) fields {
+	if fs, ok := fieldCache.Load(typ); ok {
+		return fs.(fields)
+	}
+	fs, _ := fieldCache.LoadOrStore(typ, typeFields(typ))
+	return fs.(fields)
+}
+
+// typeFields returns the fields that columns can be mapped to in a struct.
+// The fields of embedded structs are included unless a shallower field has
+// the same name.
+func typeFields(typ reflect.Type) fields {
+	var result fields
+	seen := map[string]bool{}
+
+	type level struct {
+		typ   reflect.Type
+		index []int
+	}
+	current := []level{{typ: typ}}
+	for len(current) > 0 {
+		var next []level
+		names := map[string]bool{}
+
+		for _, l := range current {
+			for i := 0; i < l.typ.NumField(); i++ {
+				sf := l.typ.Field(i)
+				index := append(append([]int(nil), l.index...), i)
+
+				tag := sf.Tag.Get("db")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+
+				if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+					if _, ok := structType(sf.Type); ok {
+						next = append(next, level{typ: sf.Type, index: index})
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+				}
+				key := foldName(name)
+				if seen[key] {
+					continue
+				}
+				names[key] = true
+				result = append(result, field{name: name, tagged: tagged, index: index})
+			}
+		}
+
+		for key := range names {
+			seen[key] = true
+		}
+		current = next
+	}
+
+	return result
+}
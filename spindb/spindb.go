@@ -0,0 +1,105 @@
+// Package spindb provides helpers for querying the Spin database drivers
+// through database/sql.
+//
+// QueryAll, QueryOne and QuerySeq scan each row into a value of type T. If T
+// is a struct, each column is stored in the field with a matching `db` tag or,
+// failing that, the field whose name matches the column ignoring case and
+// underscores:
+//
+//	type Pet struct {
+//		ID        int64   `db:"id"`
+//		Name      string  `db:"name"`
+//		Prey      *string `db:"prey"` // nullable column
+//		IsFinicky bool    `db:"is_finicky"`
+//	}
+//
+//	pets, err := spindb.QueryAll[Pet](ctx, db, "SELECT * FROM pets")
+//
+// Fields tagged `db:"-"` are ignored, and the fields of embedded structs are
+// promoted as with encoding/json. A column without a matching field is an
+// error. If T is a map[string]any, each column is stored under its name, as
+// a value of the column's scan type or nil for NULL. Any other T, such as
+// int64 or a type implementing [sql.Scanner], is scanned from a single
+// column.
+//
+// The helpers work with the pg, sqlite and mysql drivers alike, and with any
+// other database/sql driver.
+package spindb
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+)
+
+// Querier is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// QueryAll runs query and returns all the rows it produced, each scanned into
+// a T.
+func QueryAll[T any](ctx context.Context, q Querier, query string, args ...any) ([]T, error) {
+	var all []T
+	for v, err := range QuerySeq[T](ctx, q, query, args...) {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, v)
+	}
+	return all, nil
+}
+
+// QueryOne runs query and returns the first row it produced, scanned into a
+// T. It returns sql.ErrNoRows if there were no rows.
+func QueryOne[T any](ctx context.Context, q Querier, query string, args ...any) (T, error) {
+	for v, err := range QuerySeq[T](ctx, q, query, args...) {
+		return v, err
+	}
+	var zero T
+	return zero, sql.ErrNoRows
+}
+
+// QuerySeq runs query and returns an iterator over the rows it produced, each
+// scanned into a T. Rows are read from the database as the iteration
+// proceeds, so large results need not fit in memory. An error ends the
+// iteration, and stopping early closes the rows.
+func QuerySeq[T any](ctx context.Context, q Querier, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		s, err := newScanner[T](rows)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rows.Next() {
+			v, err := s.scan(rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// Exec runs a query that doesn't return rows, such as an INSERT or UPDATE.
+func Exec(ctx context.Context, q Querier, query string, args ...any) (sql.Result, error) {
+	return q.ExecContext(ctx, query, args...)
+}
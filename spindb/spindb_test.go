@@ -0,0 +1,182 @@
+package spindb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spinframework/spin-go-sdk/v3/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResults are the result sets of the fake database, keyed by query.
+var fakeResults = map[string]*dbtest.Rows{
+	"pets": {
+		Columns: []string{"id", "name", "prey", "is_finicky"},
+		Types:   []reflect.Type{reflect.TypeFor[int64](), reflect.TypeFor[string](), reflect.TypeFor[string](), reflect.TypeFor[bool]()},
+		Values: [][]driver.Value{
+			{int64(1), "Splodge", nil, false},
+			{int64(2), "Kiki", "cicadas", true},
+			{int64(3), "Slats", "rats", false},
+		},
+	},
+	"counts": {
+		Columns: []string{"count"},
+		Types:   []reflect.Type{reflect.TypeFor[int64]()},
+		Values:  [][]driver.Value{{int64(42)}},
+	},
+	"empty": {
+		Columns: []string{"id"},
+		Types:   []reflect.Type{reflect.TypeFor[int64]()},
+	},
+	"events": {
+		Columns: []string{"ID", "at", "payload"},
+		Types:   []reflect.Type{reflect.TypeFor[int64](), reflect.TypeFor[time.Time](), reflect.TypeFor[any]()},
+		Values: [][]driver.Value{
+			{int64(7), time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), []byte("x")},
+		},
+	},
+}
+
+func openFake(t *testing.T) *sql.DB {
+	return dbtest.Open(t, &dbtest.Conn{
+		Query: func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+			result, ok := fakeResults[query]
+			if !ok {
+				return nil, errors.New("no such table")
+			}
+			return result.Iter(), nil
+		},
+	})
+}
+
+type Pet struct {
+	ID        int64   `db:"id"`
+	Name      string  `db:"name"`
+	Prey      *string `db:"prey"`
+	IsFinicky bool    `db:"is_finicky"`
+	Ignored   string  `db:"-"`
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestQueryAll(t *testing.T) {
+	db := openFake(t)
+
+	pets, err := QueryAll[Pet](context.Background(), db, "pets")
+	require.NoError(t, err)
+	assert.Equal(t, []Pet{
+		{ID: 1, Name: "Splodge"},
+		{ID: 2, Name: "Kiki", Prey: ptr("cicadas"), IsFinicky: true},
+		{ID: 3, Name: "Slats", Prey: ptr("rats")},
+	}, pets)
+}
+
+func TestQueryAll_Pointers(t *testing.T) {
+	db := openFake(t)
+
+	pets, err := QueryAll[*Pet](context.Background(), db, "pets")
+	require.NoError(t, err)
+	require.Len(t, pets, 3)
+	assert.Equal(t, "Kiki", pets[1].Name)
+}
+
+func TestQueryAll_Embedded(t *testing.T) {
+	type base struct {
+		ID int64
+	}
+	type event struct {
+		base
+		At      time.Time `db:"at"`
+		Payload any
+	}
+
+	db := openFake(t)
+
+	events, err := QueryAll[event](context.Background(), db, "events")
+	require.NoError(t, err)
+	assert.Equal(t, []event{{
+		base:    base{ID: 7},
+		At:      time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		Payload: []byte("x"),
+	}}, events)
+}
+
+func TestQueryAll_Map(t *testing.T) {
+	db := openFake(t)
+
+	rows, err := QueryAll[map[string]any](context.Background(), db, "pets")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, map[string]any{"id": int64(1), "name": "Splodge", "prey": nil, "is_finicky": false}, rows[0])
+	assert.Equal(t, "cicadas", rows[1]["prey"])
+}
+
+func TestQueryAll_Errors(t *testing.T) {
+	db := openFake(t)
+	ctx := context.Background()
+
+	_, err := QueryAll[Pet](ctx, db, "missing")
+	assert.EqualError(t, err, "no such table")
+
+	type partial struct {
+		ID int64
+	}
+	_, err = QueryAll[partial](ctx, db, "pets")
+	assert.EqualError(t, err, `spindb: column "name" has no matching field in spindb.partial`)
+
+	_, err = QueryAll[int64](ctx, db, "pets")
+	assert.EqualError(t, err, "spindb: cannot scan 4 columns into int64")
+
+	type wrongType struct {
+		ID        int64 `db:"id"`
+		Name      int   `db:"name"`
+		Prey      *string
+		IsFinicky bool
+	}
+	_, err = QueryAll[wrongType](ctx, db, "pets")
+	assert.ErrorContains(t, err, `spindb: sql: Scan error on column index 1, name "name"`)
+}
+
+func TestQueryOne(t *testing.T) {
+	db := openFake(t)
+	ctx := context.Background()
+
+	count, err := QueryOne[int64](ctx, db, "counts")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+
+	pet, err := QueryOne[Pet](ctx, db, "pets")
+	require.NoError(t, err)
+	assert.Equal(t, "Splodge", pet.Name)
+
+	_, err = QueryOne[int64](ctx, db, "empty")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	var nullable sql.NullInt64
+	nullable, err = QueryOne[sql.NullInt64](ctx, db, "counts")
+	require.NoError(t, err)
+	assert.Equal(t, sql.NullInt64{Int64: 42, Valid: true}, nullable)
+}
+
+func TestQuerySeq(t *testing.T) {
+	db := openFake(t)
+
+	var names []string
+	for pet, err := range QuerySeq[Pet](context.Background(), db, "pets") {
+		require.NoError(t, err)
+		names = append(names, pet.Name)
+		if len(names) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"Splodge", "Kiki"}, names)
+
+	// Stopping early must release the connection.
+	assert.Eventually(t, func() bool { return db.Stats().InUse == 0 }, time.Second, time.Millisecond)
+}
@@ -24,4 +24,31 @@
 //		}
 //		pets = append(pets, &pet)
 //	}
+//
+// Arguments of type time.Time are stored as ISO8601 TEXT in UTC, using
+// TimeFormat. Wrap them in UnixTime to store an INTEGER number of seconds
+// instead. Arguments that implement driver.Valuer, pointers and named basic
+// types are converted as well; anything else is rejected with a
+// *ConversionError.
+//
+// Statements may use the positional "?" and "?NNN" parameters or the named
+// ":name", "@name" and "$name" parameters, which are bound from sql.Named
+// arguments:
+//
+//	db.Exec("UPDATE pets SET prey = :prey WHERE id = :id",
+//		sql.Named("id", 4), sql.Named("prey", "bananas"))
+//
+// Arguments are matched to parameters before the statement is sent to the
+// host, so a missing or unknown argument is reported as an error without
+// running the statement.
+//
+// Errors opening a database are reported as ErrNoSuchDatabase,
+// ErrAccessDenied and so on, while errors from SQLite itself are reported as
+// an *IOError whose Code identifies the kind of failure where it can be
+// determined from the host's message:
+//
+//	var ioErr *sqlite.IOError
+//	if errors.As(err, &ioErr) && ioErr.Code == sqlite.CodeConstraintUnique {
+//		// a pet with that ID already exists
+//	}
 package sqlite
@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sqlite "github.com/spinframework/spin-go-sdk/v3/imports/spin_sqlite_3_1_0_sqlite"
+)
+
+var (
+	// ErrNoSuchDatabase is returned when the named database does not exist.
+	ErrNoSuchDatabase = errors.New("no such database")
+	// ErrAccessDenied is returned when the component is not allowed to use
+	// the named database.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInvalidConnection is returned when the connection is no longer
+	// valid.
+	ErrInvalidConnection = errors.New("invalid connection")
+	// ErrDatabaseFull is returned when the database has reached its storage
+	// limit.
+	ErrDatabaseFull = errors.New("database full")
+)
+
+// IOError is an error reported by SQLite while running a statement, such as a
+// constraint violation or a syntax error.
+type IOError struct {
+	// Code is the result code of the error, parsed from Message. It is the
+	// extended result code where one could be determined, such as
+	// CodeConstraintUnique, and 0 if the message was not recognized.
+	Code ErrorCode
+	// Message is the error text reported by the host.
+	Message string
+}
+
+func (e *IOError) Error() string {
+	return e.Message
+}
+
+// ErrorCode is a SQLite result code. The low eight bits of an extended result
+// code are its primary result code; see https://sqlite.org/rescode.html.
+type ErrorCode int
+
+// Primary returns the primary result code of c, such as CodeConstraint for
+// CodeConstraintUnique.
+func (c ErrorCode) Primary() ErrorCode {
+	return c & 0xff
+}
+
+// String returns the name of the code, such as "SQLITE_CONSTRAINT_UNIQUE".
+func (c ErrorCode) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return "ErrorCode(" + strconv.Itoa(int(c)) + ")"
+}
+
+// Primary result codes.
+const (
+	CodeError      ErrorCode = 1
+	CodeInternal   ErrorCode = 2
+	CodePerm       ErrorCode = 3
+	CodeAbort      ErrorCode = 4
+	CodeBusy       ErrorCode = 5
+	CodeLocked     ErrorCode = 6
+	CodeNoMem      ErrorCode = 7
+	CodeReadOnly   ErrorCode = 8
+	CodeInterrupt  ErrorCode = 9
+	CodeIOErr      ErrorCode = 10
+	CodeCorrupt    ErrorCode = 11
+	CodeNotFound   ErrorCode = 12
+	CodeFull       ErrorCode = 13
+	CodeCantOpen   ErrorCode = 14
+	CodeProtocol   ErrorCode = 15
+	CodeEmpty      ErrorCode = 16
+	CodeSchema     ErrorCode = 17
+	CodeTooBig     ErrorCode = 18
+	CodeConstraint ErrorCode = 19
+	CodeMismatch   ErrorCode = 20
+	CodeMisuse     ErrorCode = 21
+	CodeNoLFS      ErrorCode = 22
+	CodeAuth       ErrorCode = 23
+	CodeFormat     ErrorCode = 24
+	CodeRange      ErrorCode = 25
+	CodeNotADB     ErrorCode = 26
+)
+
+// Extended result codes for busy, locked and constraint errors.
+const (
+	CodeBusyRecovery         ErrorCode = 261
+	CodeBusySnapshot         ErrorCode = 517
+	CodeBusyTimeout          ErrorCode = 773
+	CodeLockedSharedCache    ErrorCode = 262
+	CodeLockedVTab           ErrorCode = 518
+	CodeConstraintCheck      ErrorCode = 275
+	CodeConstraintCommitHook ErrorCode = 531
+	CodeConstraintForeignKey ErrorCode = 787
+	CodeConstraintFunction   ErrorCode = 1043
+	CodeConstraintNotNull    ErrorCode = 1299
+	CodeConstraintPrimaryKey ErrorCode = 1555
+	CodeConstraintTrigger    ErrorCode = 1811
+	CodeConstraintUnique     ErrorCode = 2067
+	CodeConstraintVTab       ErrorCode = 2323
+	CodeConstraintRowID      ErrorCode = 2579
+	CodeConstraintPinned     ErrorCode = 2835
+	CodeConstraintDataType   ErrorCode = 3091
+)
+
+var codeNames = map[ErrorCode]string{
+	CodeError:                "SQLITE_ERROR",
+	CodeInternal:             "SQLITE_INTERNAL",
+	CodePerm:                 "SQLITE_PERM",
+	CodeAbort:                "SQLITE_ABORT",
+	CodeBusy:                 "SQLITE_BUSY",
+	CodeLocked:               "SQLITE_LOCKED",
+	CodeNoMem:                "SQLITE_NOMEM",
+	CodeReadOnly:             "SQLITE_READONLY",
+	CodeInterrupt:            "SQLITE_INTERRUPT",
+	CodeIOErr:                "SQLITE_IOERR",
+	CodeCorrupt:              "SQLITE_CORRUPT",
+	CodeNotFound:             "SQLITE_NOTFOUND",
+	CodeFull:                 "SQLITE_FULL",
+	CodeCantOpen:             "SQLITE_CANTOPEN",
+	CodeProtocol:             "SQLITE_PROTOCOL",
+	CodeEmpty:                "SQLITE_EMPTY",
+	CodeSchema:               "SQLITE_SCHEMA",
+	CodeTooBig:               "SQLITE_TOOBIG",
+	CodeConstraint:           "SQLITE_CONSTRAINT",
+	CodeMismatch:             "SQLITE_MISMATCH",
+	CodeMisuse:               "SQLITE_MISUSE",
+	CodeNoLFS:                "SQLITE_NOLFS",
+	CodeAuth:                 "SQLITE_AUTH",
+	CodeFormat:               "SQLITE_FORMAT",
+	CodeRange:                "SQLITE_RANGE",
+	CodeNotADB:               "SQLITE_NOTADB",
+	CodeBusyRecovery:         "SQLITE_BUSY_RECOVERY",
+	CodeBusySnapshot:         "SQLITE_BUSY_SNAPSHOT",
+	CodeBusyTimeout:          "SQLITE_BUSY_TIMEOUT",
+	CodeLockedSharedCache:    "SQLITE_LOCKED_SHAREDCACHE",
+	CodeLockedVTab:           "SQLITE_LOCKED_VTAB",
+	CodeConstraintCheck:      "SQLITE_CONSTRAINT_CHECK",
+	CodeConstraintCommitHook: "SQLITE_CONSTRAINT_COMMITHOOK",
+	CodeConstraintForeignKey: "SQLITE_CONSTRAINT_FOREIGNKEY",
+	CodeConstraintFunction:   "SQLITE_CONSTRAINT_FUNCTION",
+	CodeConstraintNotNull:    "SQLITE_CONSTRAINT_NOTNULL",
+	CodeConstraintPrimaryKey: "SQLITE_CONSTRAINT_PRIMARYKEY",
+	CodeConstraintTrigger:    "SQLITE_CONSTRAINT_TRIGGER",
+	CodeConstraintUnique:     "SQLITE_CONSTRAINT_UNIQUE",
+	CodeConstraintVTab:       "SQLITE_CONSTRAINT_VTAB",
+	CodeConstraintRowID:      "SQLITE_CONSTRAINT_ROWID",
+	CodeConstraintPinned:     "SQLITE_CONSTRAINT_PINNED",
+	CodeConstraintDataType:   "SQLITE_CONSTRAINT_DATATYPE",
+}
+
+var codesByName = func() map[string]ErrorCode {
+	codes := make(map[string]ErrorCode, len(codeNames))
+	for code, name := range codeNames {
+		codes[name] = code
+	}
+	return codes
+}()
+
+var (
+	// numericCode matches the numeric codes in rusqlite's messages, such as
+	// "Error code 2067: ..." or "extended_code: 2067".
+	numericCode = regexp.MustCompile(`(?:Error code|extended_code:) ([0-9]+)`)
+	// namedCode matches code names such as "SQLITE_CONSTRAINT_UNIQUE".
+	namedCode = regexp.MustCompile(`\bSQLITE_[A-Z_]+\b`)
+)
+
+// messageCodes maps the messages SQLite reports for common errors to their
+// codes, for hosts that report only the message. Longer, more specific
+// messages come first.
+var messageCodes = []struct {
+	message string
+	code    ErrorCode
+}{
+	{"UNIQUE constraint failed", CodeConstraintUnique},
+	{"NOT NULL constraint failed", CodeConstraintNotNull},
+	{"FOREIGN KEY constraint failed", CodeConstraintForeignKey},
+	{"CHECK constraint failed", CodeConstraintCheck},
+	{"cannot store", CodeConstraintDataType},
+	{"database table is locked", CodeLocked},
+	{"database schema is locked", CodeLocked},
+	{"database is locked", CodeBusy},
+	{"attempt to write a readonly database", CodeReadOnly},
+	{"database or disk is full", CodeFull},
+	{"database disk image is malformed", CodeCorrupt},
+	{"file is not a database", CodeNotADB},
+	{"unable to open database file", CodeCantOpen},
+	{"string or blob too big", CodeTooBig},
+	{"datatype mismatch", CodeMismatch},
+	{"interrupted", CodeInterrupt},
+	{"syntax error", CodeError},
+	{"no such table", CodeError},
+	{"no such column", CodeError},
+}
+
+// parseErrorCode determines the result code of an error message, or returns
+// 0 if the message is not recognized.
+func parseErrorCode(message string) ErrorCode {
+	if m := numericCode.FindStringSubmatch(message); m != nil {
+		if code, err := strconv.Atoi(m[1]); err == nil {
+			return ErrorCode(code)
+		}
+	}
+	for _, name := range namedCode.FindAllString(message, -1) {
+		if code, ok := codesByName[name]; ok {
+			return code
+		}
+	}
+	for _, mc := range messageCodes {
+		if strings.Contains(message, mc.message) {
+			return mc.code
+		}
+	}
+	return 0
+}
+
+func toError(err sqlite.Error) error {
+	switch err.Tag() {
+	case sqlite.ErrorNoSuchDatabase:
+		return ErrNoSuchDatabase
+	case sqlite.ErrorAccessDenied:
+		return ErrAccessDenied
+	case sqlite.ErrorInvalidConnection:
+		return ErrInvalidConnection
+	case sqlite.ErrorDatabaseFull:
+		return ErrDatabaseFull
+	case sqlite.ErrorIo:
+		return &IOError{Code: parseErrorCode(err.Io()), Message: err.Io()}
+	default:
+		return fmt.Errorf("unknown error from runtime (tag %d)", err.Tag())
+	}
+}
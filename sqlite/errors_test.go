@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+
+	sqlite "github.com/spinframework/spin-go-sdk/v3/imports/spin_sqlite_3_1_0_sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorCode(t *testing.T) {
+	tests := []struct {
+		message string
+		want    ErrorCode
+	}{
+		{"UNIQUE constraint failed: pets.id", CodeConstraintUnique},
+		{"NOT NULL constraint failed: pets.name", CodeConstraintNotNull},
+		{"FOREIGN KEY constraint failed", CodeConstraintForeignKey},
+		{"CHECK constraint failed: age > 0", CodeConstraintCheck},
+		{"cannot store TEXT value in INTEGER column pets.id", CodeConstraintDataType},
+		{"database is locked", CodeBusy},
+		{"database table is locked: pets", CodeLocked},
+		{`near "SELEC": syntax error`, CodeError},
+		{"no such table: pets", CodeError},
+		{"Error code 2067: A UNIQUE constraint failed", CodeConstraintUnique},
+		{`SqliteFailure(Error { code: DatabaseBusy, extended_code: 5 }, Some("database is locked"))`, CodeBusy},
+		{"SQLITE_CONSTRAINT_PRIMARYKEY: UNIQUE constraint failed: pets.id", CodeConstraintPrimaryKey},
+		{"SQLITE_UNKNOWN_THING: disk is full", 0},
+		{"something else went wrong", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseErrorCode(tt.message))
+		})
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, CodeConstraint, CodeConstraintUnique.Primary())
+	assert.Equal(t, CodeBusy, CodeBusyTimeout.Primary())
+	assert.Equal(t, CodeBusy, CodeBusy.Primary())
+	assert.Equal(t, "SQLITE_CONSTRAINT_UNIQUE", CodeConstraintUnique.String())
+	assert.Equal(t, "ErrorCode(9999)", ErrorCode(9999).String())
+}
+
+func TestToError(t *testing.T) {
+	assert.ErrorIs(t, toError(sqlite.MakeErrorNoSuchDatabase()), ErrNoSuchDatabase)
+	assert.ErrorIs(t, toError(sqlite.MakeErrorAccessDenied()), ErrAccessDenied)
+	assert.ErrorIs(t, toError(sqlite.MakeErrorInvalidConnection()), ErrInvalidConnection)
+	assert.ErrorIs(t, toError(sqlite.MakeErrorDatabaseFull()), ErrDatabaseFull)
+
+	err := toError(sqlite.MakeErrorIo("UNIQUE constraint failed: pets.id"))
+	var ioErr *IOError
+	require.True(t, errors.As(err, &ioErr))
+	assert.Equal(t, CodeConstraintUnique, ioErr.Code)
+	assert.EqualError(t, err, "UNIQUE constraint failed: pets.id")
+}
+
+func TestToRow(t *testing.T) {
+	row, err := toRow([]sqlite.Value{sqlite.MakeValueInteger(1), sqlite.MakeValueText("a"), sqlite.MakeValueNull()})
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(1), "a", nil}, row)
+}
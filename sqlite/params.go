@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"strconv"
+
+	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
+)
+
+// parseParams returns the parameters of a SQLite statement in index order,
+// naming each one by its name without the prefix, or "" for "?" and "?NNN"
+// parameters.
+//
+// Indexes are assigned as SQLite assigns them: "?NNN" has index NNN, and "?"
+// or a named parameter (":name", "@name" or "$name") seen for the first time
+// has the index after the largest assigned so far. A named parameter that
+// appears more than once with the same prefix refers to the same index, while
+// the same name with different prefixes, such as ":id" and "@id", gives
+// separate parameters that are both bound from the argument of that name.
+// String literals, quoted identifiers and comments are skipped.
+func parseParams(query string) []string {
+	var params []string
+	index := map[string]int{}
+
+	assign := func(i int, name string) {
+		for len(params) < i {
+			params = append(params, "")
+		}
+		if name != "" {
+			params[i-1] = name
+		}
+	}
+
+	for i := 0; i < len(query); {
+		if j := spindb.SQLite.Skip(query, i); j > i {
+			i = j
+			continue
+		}
+		switch c := query[i]; c {
+		case '?':
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+			n := len(params) + 1
+			if j > i+1 {
+				if parsed, err := strconv.Atoi(query[i+1 : j]); err == nil && parsed > 0 {
+					n = parsed
+				}
+			}
+			assign(n, "")
+			i = j
+		case ':', '@', '$':
+			j := i + 1
+			for j < len(query) && isIdentChar(query[j]) {
+				j++
+			}
+			if j == i+1 {
+				i++
+				continue
+			}
+			token := query[i:j]
+			if _, ok := index[token]; !ok {
+				index[token] = len(params) + 1
+				assign(index[token], token[1:])
+			}
+			i = j
+		default:
+			if isIdentChar(c) {
+				// Skip whole words so that "$" and digits inside an
+				// identifier are not mistaken for parameters.
+				for i < len(query) && (isIdentChar(query[i]) || query[i] == '$') {
+					i++
+				}
+			} else {
+				i++
+			}
+		}
+	}
+
+	return params
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
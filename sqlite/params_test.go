@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{{
+		name:  "none",
+		query: "SELECT 1",
+		want:  nil,
+	}, {
+		name:  "positional",
+		query: "SELECT * FROM t WHERE a = ? AND b = ?",
+		want:  []string{"", ""},
+	}, {
+		name:  "numbered",
+		query: "SELECT ?2, ?1",
+		want:  []string{"", ""},
+	}, {
+		name:  "named prefixes",
+		query: "INSERT INTO t VALUES (:id, @name, $email)",
+		want:  []string{"id", "name", "email"},
+	}, {
+		name:  "repeated name",
+		query: "SELECT * FROM t WHERE a = :id OR b = :id OR c = :other",
+		want:  []string{"id", "other"},
+	}, {
+		name:  "same name with different prefixes",
+		query: "SELECT * FROM t WHERE a = :id OR b = @id OR c = :other",
+		want:  []string{"id", "id", "other"},
+	}, {
+		name:  "mixed",
+		query: "SELECT ?, :id, ?",
+		want:  []string{"", "id", ""},
+	}, {
+		name:  "quoted",
+		query: `SELECT ':a', "@b", [$c], ` + "`?`" + `, 'it''s :d' FROM t WHERE x = :x`,
+		want:  []string{"x"},
+	}, {
+		name:  "comments",
+		query: "SELECT :a -- :b ?\n, /* @c ? */ ?",
+		want:  []string{"a", ""},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseParams(tt.query))
+		})
+	}
+}
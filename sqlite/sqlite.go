@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"io"
+	"math"
+	"time"
 
 	sqlite "github.com/spinframework/spin-go-sdk/v3/imports/spin_sqlite_3_1_0_sqlite"
 	spindb "github.com/spinframework/spin-go-sdk/v3/internal/db"
@@ -30,7 +33,7 @@ func (c *conn) Close() error {
 
 // Prepare returns a prepared statement, bound to this connection.
 func (c *conn) Prepare(query string) (driver.Stmt, error) {
-	return &stmt{conn: c, query: query}, nil
+	return &stmt{conn: c, query: query, params: parseParams(query)}, nil
 }
 
 // Begin isn't supported.
@@ -38,6 +41,108 @@ func (c *conn) Begin() (driver.Tx, error) {
 	return nil, errors.New("transactions are unsupported by this driver")
 }
 
+// QueryContext executes a query that may return rows, such as a SELECT.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.query(ctx, query, parseParams(query), args)
+}
+
+// ExecContext executes a query that doesn't return rows, such as an INSERT or
+// UPDATE.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.exec(ctx, query, parseParams(query), args)
+}
+
+type executeResult = wit.Result[wit.Tuple3[[]string, *wit.StreamReader[sqlite.RowResult], *wit.FutureReader[wit.Result[wit.Unit, sqlite.Error]]], sqlite.Error]
+
+// execute binds args to the parameters of query, which are described by
+// params, and runs it. Binding errors are returned before calling the host.
+func (c *conn) execute(ctx context.Context, query string, params []string, args []driver.NamedValue) (executeResult, error) {
+	values, err := spindb.BindArgs(params, args)
+	if err != nil {
+		return executeResult{}, err
+	}
+
+	sqliteParams, err := toSqliteValues(values)
+	if err != nil {
+		return executeResult{}, err
+	}
+
+	return spindb.Await(ctx, func() executeResult {
+		return c.spinConn.ExecuteAsync(query, sqliteParams)
+	}, func(results executeResult) {
+		if results.IsOk() {
+			results.Ok().F1.Drop()
+			results.Ok().F2.Drop()
+		}
+	})
+}
+
+func (c *conn) query(ctx context.Context, query string, params []string, args []driver.NamedValue) (driver.Rows, error) {
+	results, err := c.execute(ctx, query, params, args)
+	if err != nil {
+		return nil, err
+	}
+	if results.IsErr() {
+		return nil, toError(results.Err())
+	}
+
+	tuple := results.Ok()
+
+	rows := &rows{
+		ctx:     ctx,
+		columns: tuple.F0,
+		stream:  tuple.F1,
+		future:  tuple.F2,
+	}
+
+	rows.next = rows.pull()
+
+	return rows, nil
+}
+
+func (c *conn) exec(ctx context.Context, query string, params []string, args []driver.NamedValue) (driver.Result, error) {
+	queryResult, err := c.execute(ctx, query, params, args)
+	if err != nil {
+		return nil, err
+	}
+	if queryResult.IsErr() {
+		return &result{}, toError(queryResult.Err())
+	}
+
+	tuple := queryResult.Ok()
+	tuple.F1.Drop()
+
+	// Read drops the future when it completes, so there is nothing left for
+	// release to drop, and the future must not be dropped here while the read
+	// is pending.
+	rowsResult, err := spindb.Await(ctx, tuple.F2.Read, func(wit.Result[wit.Unit, sqlite.Error]) {})
+	if err != nil {
+		return nil, err
+	}
+	if rowsResult.IsErr() {
+		return nil, toError(rowsResult.Err())
+	}
+
+	return &result{
+		insertID:     c.spinConn.LastInsertRowidAsync(),
+		rowsAffected: int64(c.spinConn.ChangesAsync()),
+	}, nil
+}
+
+// CheckNamedValue converts a query argument to a SQLite value, returning a
+// *ConversionError if its type is not supported.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return spindb.CheckNamedValue(nv, toSqliteValue)
+}
+
+var _ driver.NamedValueChecker = (*conn)(nil)
+var _ driver.QueryerContext = (*conn)(nil)
+var _ driver.ExecerContext = (*conn)(nil)
+
+// ConversionError is returned when a query argument cannot be converted to a
+// SQLite value.
+type ConversionError = spindb.ConversionError
+
 // connector implements driver.Connector.
 type connector struct {
 	conn *conn
@@ -45,12 +150,12 @@ type connector struct {
 }
 
 // Connect returns a connection to the database.
-func (d *connector) Connect(_ context.Context) (driver.Conn, error) {
+func (d *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	if d.conn != nil {
 		return d.conn, nil
 	}
 
-	return d.Open(d.name)
+	return d.open(ctx, d.name)
 }
 
 // Driver returns the underlying Driver of the Connector.
@@ -60,7 +165,20 @@ func (d *connector) Driver() driver.Driver {
 
 // Open returns a new connection to the database.
 func (d *connector) Open(name string) (driver.Conn, error) {
-	results := sqlite.ConnectionOpenAsync(name)
+	return d.open(context.Background(), name)
+}
+
+func (d *connector) open(ctx context.Context, name string) (driver.Conn, error) {
+	results, err := spindb.Await(ctx, func() wit.Result[*sqlite.Connection, sqlite.Error] {
+		return sqlite.ConnectionOpenAsync(name)
+	}, func(results wit.Result[*sqlite.Connection, sqlite.Error]) {
+		if results.IsOk() {
+			results.Ok().Drop()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
 	if results.IsErr() {
 		return nil, toError(results.Err())
 	}
@@ -78,6 +196,7 @@ func (d *connector) Close() error {
 }
 
 type rows struct {
+	ctx     context.Context
 	columns []string
 	next    []any
 	stream  *wit.StreamReader[sqlite.RowResult]
@@ -94,8 +213,10 @@ func (r *rows) Columns() []string {
 
 // Close closes the rows iterator.
 func (r *rows) Close() error {
-	r.stream.Drop()
-	r.future.Drop()
+	if r.stream != nil {
+		r.stream.Drop()
+		r.future.Drop()
+	}
 	r.stream = nil
 	r.future = nil
 	r.next = nil
@@ -103,19 +224,39 @@ func (r *rows) Close() error {
 	return nil
 }
 
+// pull reads the next row. At the end of the stream it returns nil and records
+// the outcome of the query. If the context is done first, the context's error
+// is recorded instead, and the stream and future are dropped once the pending
+// read completes rather than by Close.
 func (r *rows) pull() []any {
-	buffer := []sqlite.RowResult{sqlite.RowResult{}}
-	if r.stream.Read(buffer) == 1 {
-		return toRow(buffer[0].Values)
-	} else {
-		result := r.future.Read()
+	stream, future := r.stream, r.future
+	next, err := spindb.Await(r.ctx, func() spindb.Row {
+		buffer := []sqlite.RowResult{sqlite.RowResult{}}
+		if stream.Read(buffer) == 1 {
+			values, err := toRow(buffer[0].Values)
+			return spindb.Row{Values: values, Err: err}
+		}
+		result := future.Read()
 		if result.IsOk() {
-			r.result = io.EOF
-		} else {
-			r.result = toError(result.Err())
+			return spindb.Row{Err: io.EOF}
 		}
+		return spindb.Row{Err: toError(result.Err())}
+	}, func(spindb.Row) {
+		stream.Drop()
+		future.Drop()
+	})
+	if err != nil {
+		// The pending read owns the stream and future now, and release
+		// drops them once it completes.
+		r.stream = nil
+		r.future = nil
+		r.result = err
 		return nil
 	}
+	if next.Values == nil {
+		r.result = next.Err
+	}
+	return next.Values
 }
 
 // Next moves the cursor to the next row.
@@ -150,12 +291,14 @@ func (r *rows) NextResultSet() error {
 }
 
 type stmt struct {
-	conn  *conn
-	query string
+	conn   *conn
+	query  string
+	params []string
 }
 
 var _ driver.Stmt = (*stmt)(nil)
-var _ driver.ColumnConverter = (*stmt)(nil)
+var _ driver.StmtQueryContext = (*stmt)(nil)
+var _ driver.StmtExecContext = (*stmt)(nil)
 
 // Close closes the statement.
 func (s *stmt) Close() error {
@@ -164,64 +307,30 @@ func (s *stmt) Close() error {
 
 // NumInput returns the number of placeholder parameters.
 func (s *stmt) NumInput() int {
-	// Golang sql won't sanity check argument counts before Query.
-	return -1
+	return len(s.params)
 }
 
 // Query executes a query that may return rows, such as a SELECT.
 func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
-	sqliteParams := make([]sqlite.Value, len(args))
-	for i, v := range args {
-		sqliteParams[i] = toSqliteValue(v)
-	}
-
-	results := s.conn.spinConn.ExecuteAsync(s.query, sqliteParams)
-	if results.IsErr() {
-		return nil, toError(results.Err())
-	}
-
-	tuple := results.Ok()
-
-	rows := &rows{
-		columns: tuple.F0,
-		stream:  tuple.F1,
-		future:  tuple.F2,
-	}
-
-	rows.next = rows.pull()
-
-	return rows, nil
+	return s.conn.query(context.Background(), s.query, s.params, spindb.ToNamedValues(args))
 }
 
 // Exec executes a query that doesn't return rows, such as an INSERT or
 // UPDATE.
 func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
-	sqliteParams := make([]sqlite.Value, len(args))
-	for i, v := range args {
-		sqliteParams[i] = toSqliteValue(v)
-	}
-
-	queryResult := s.conn.spinConn.ExecuteAsync(s.query, sqliteParams)
-	if queryResult.IsErr() {
-		return &result{}, toError(queryResult.Err())
-	}
-
-	tuple := queryResult.Ok()
-	tuple.F1.Drop()
-
-	if rowsResult := tuple.F2.Read(); rowsResult.IsErr() {
-		return nil, toError(rowsResult.Err())
-	}
+	return s.conn.exec(context.Background(), s.query, s.params, spindb.ToNamedValues(args))
+}
 
-	return &result{
-		insertID:     s.conn.spinConn.LastInsertRowidAsync(),
-		rowsAffected: int64(s.conn.spinConn.ChangesAsync()),
-	}, nil
+// QueryContext executes a query that may return rows, such as a SELECT.
+// If ctx is done before the query completes, the host row stream is dropped.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.query(ctx, s.query, s.params, args)
 }
 
-// ColumnConverter returns GlobalParameterConverter to prevent using driver.DefaultParameterConverter.
-func (s *stmt) ColumnConverter(_ int) driver.ValueConverter {
-	return spindb.GlobalParameterConverter
+// ExecContext executes a query that doesn't return rows, such as an INSERT or
+// UPDATE.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.exec(ctx, s.query, s.params, args)
 }
 
 type result struct {
@@ -236,59 +345,73 @@ func (r result) RowsAffected() (int64, error) {
 	return r.rowsAffected, nil
 }
 
-func toSqliteValue(x any) sqlite.Value {
+func toSqliteValues(args []driver.Value) ([]sqlite.Value, error) {
+	sqliteParams := make([]sqlite.Value, len(args))
+	for i, v := range args {
+		p, err := toSqliteValue(v)
+		if err != nil {
+			return nil, err
+		}
+		sqliteParams[i] = p
+	}
+	return sqliteParams, nil
+}
+
+func toSqliteValue(x any) (sqlite.Value, error) {
 	switch v := x.(type) {
+	case sqlite.Value:
+		return v, nil
+	case bool:
+		if v {
+			return sqlite.MakeValueInteger(1), nil
+		}
+		return sqlite.MakeValueInteger(0), nil
 	case int8:
-		return sqlite.MakeValueInteger(int64(v))
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case int16:
-		return sqlite.MakeValueInteger(int64(v))
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case int32:
-		return sqlite.MakeValueInteger(int64(v))
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case int64:
-		return sqlite.MakeValueInteger(v)
+		return sqlite.MakeValueInteger(v), nil
 	case int:
-		return sqlite.MakeValueInteger(int64(v))
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case uint8:
-		return sqlite.MakeValueInteger(int64(v))
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case uint16:
-		return sqlite.MakeValueInteger(int64(v))
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case uint32:
-		return sqlite.MakeValueInteger(int64(v))
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case uint64:
-		return sqlite.MakeValueInteger(int64(v))
+		if v > math.MaxInt64 {
+			return sqlite.Value{}, &ConversionError{Value: x, Err: errors.New("value overflows int64")}
+		}
+		return sqlite.MakeValueInteger(int64(v)), nil
 	case float32:
-		return sqlite.MakeValueReal(float64(v))
+		return sqlite.MakeValueReal(float64(v)), nil
 	case float64:
-		return sqlite.MakeValueReal(v)
+		return sqlite.MakeValueReal(v), nil
 	case string:
-		return sqlite.MakeValueText(v)
+		return sqlite.MakeValueText(v), nil
 	case []byte:
-		return sqlite.MakeValueBlob(v)
+		return sqlite.MakeValueBlob(v), nil
+	case time.Time:
+		return sqlite.MakeValueText(v.UTC().Format(TimeFormat)), nil
 	case nil:
-		return sqlite.MakeValueNull()
-	default:
-		panic("unknown value type")
+		return sqlite.MakeValueNull(), nil
 	}
-}
 
-func toError(err sqlite.Error) error {
-	switch err.Tag() {
-	case sqlite.ErrorNoSuchDatabase:
-		return errors.New("no such database")
-	case sqlite.ErrorAccessDenied:
-		return errors.New("access denied")
-	case sqlite.ErrorInvalidConnection:
-		return errors.New("invalid connection")
-	case sqlite.ErrorDatabaseFull:
-		return errors.New("database full")
-	case sqlite.ErrorIo:
-		return errors.New(err.Io())
-	default:
-		panic("unreachable code")
+	v, ok, err := spindb.Indirect(x)
+	if err != nil {
+		return sqlite.Value{}, err
+	}
+	if !ok {
+		return sqlite.Value{}, &ConversionError{Value: x}
 	}
+	return toSqliteValue(v)
 }
 
-func toRow(row []sqlite.Value) []any {
+func toRow(row []sqlite.Value) ([]any, error) {
 	result := make([]any, len(row))
 	for i, v := range row {
 		switch v.Tag() {
@@ -303,9 +426,9 @@ func toRow(row []sqlite.Value) []any {
 		case sqlite.ValueNull:
 			result[i] = nil
 		default:
-			panic("unreachable code")
+			return nil, fmt.Errorf("unknown value type from runtime (tag %d)", v.Tag())
 		}
 	}
 
-	return result
+	return result, nil
 }
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	sqlite "github.com/spinframework/spin-go-sdk/v3/imports/spin_sqlite_3_1_0_sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSqliteValue(t *testing.T) {
+	n := 12
+	tests := []struct {
+		name string
+		src  any
+		want sqlite.Value
+	}{{
+		name: "bool",
+		src:  true,
+		want: sqlite.MakeValueInteger(1),
+	}, {
+		name: "time.Time",
+		src:  time.Date(2024, time.March, 15, 10, 30, 0, 500000000, time.FixedZone("CET", 3600)),
+		want: sqlite.MakeValueText("2024-03-15T09:30:00.500000000Z"),
+	}, {
+		name: "UnixTime",
+		src:  UnixTime(time.Unix(1710495000, 0)),
+		want: sqlite.MakeValueInteger(1710495000),
+	}, {
+		name: "pointer",
+		src:  &n,
+		want: sqlite.MakeValueInteger(12),
+	}, {
+		name: "nil pointer",
+		src:  (*int)(nil),
+		want: sqlite.MakeValueNull(),
+	}, {
+		name: "valid sql.NullTime",
+		src:  sql.NullTime{Time: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), Valid: true},
+		want: sqlite.MakeValueText("2024-03-15T00:00:00.000000000Z"),
+	}, {
+		name: "null sql.NullInt64",
+		src:  sql.NullInt64{},
+		want: sqlite.MakeValueNull(),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toSqliteValue(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckNamedValue(t *testing.T) {
+	c := &conn{}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 1, Value: []int{1, 2}}
+		err := c.CheckNamedValue(nv)
+
+		var convErr *ConversionError
+		require.True(t, errors.As(err, &convErr))
+		assert.Equal(t, 1, convErr.Ordinal)
+		assert.EqualError(t, convErr, "unsupported type []int")
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		nv := &driver.NamedValue{Ordinal: 3, Value: uint64(math.MaxUint64)}
+		err := c.CheckNamedValue(nv)
+
+		var convErr *ConversionError
+		require.True(t, errors.As(err, &convErr))
+		assert.Equal(t, 3, convErr.Ordinal)
+		assert.Error(t, convErr.Err)
+	})
+}
+
+func TestUnixTime_Scan(t *testing.T) {
+	var ut UnixTime
+	require.NoError(t, ut.Scan(int64(1710495000)))
+	assert.Equal(t, time.Unix(1710495000, 0).UTC(), time.Time(ut))
+
+	require.NoError(t, ut.Scan(nil))
+	assert.True(t, time.Time(ut).IsZero())
+
+	assert.Error(t, ut.Scan("2024-03-15"))
+}
+
+func TestTimeFormatSorts(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)
+	times := []time.Time{base, base.Add(50 * time.Millisecond), base.Add(100 * time.Millisecond), base.Add(time.Second)}
+	for i := 1; i < len(times); i++ {
+		assert.Less(t, times[i-1].Format(TimeFormat), times[i].Format(TimeFormat))
+	}
+}
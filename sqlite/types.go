@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// TimeFormat is the ISO8601 layout used to store time.Time arguments as TEXT.
+// Times are converted to UTC first and always carry nine fractional digits, so
+// stored values of the same width sort chronologically and work with SQLite's
+// date and time functions.
+const TimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// UnixTime is a time.Time that is stored as an INTEGER number of seconds since
+// the Unix epoch, rather than as ISO8601 TEXT.
+type UnixTime time.Time
+
+// Scan implements [sql.Scanner] so UnixTime can be used as a scan destination.
+func (t *UnixTime) Scan(src any) error {
+	switch src := src.(type) {
+	case int64:
+		*t = UnixTime(time.Unix(src, 0).UTC())
+	case nil:
+		*t = UnixTime{}
+	default:
+		return fmt.Errorf("sqlite: cannot scan %T into *UnixTime", src)
+	}
+	return nil
+}
+
+// Value implements [driver.Valuer] so UnixTime can be used as a query parameter.
+func (t UnixTime) Value() (driver.Value, error) {
+	return time.Time(t).Unix(), nil
+}
@@ -0,0 +1,17 @@
+module github.com/spinframework/spin-go-sdk/v3/testdata/http-response
+
+go 1.25.5
+
+require github.com/spinframework/spin-go-sdk/v3 v3.0.0
+
+require (
+	github.com/apparentlymart/go-userdirs v0.0.0-20200915174352-b0c018a67c13 // indirect
+	github.com/bytecodealliance/componentize-go v0.3.3 // indirect
+	github.com/gofrs/flock v0.13.0 // indirect
+	go.bytecodealliance.org/pkg v0.2.1 // indirect
+	golang.org/x/sys v0.37.0 // indirect
+)
+
+replace github.com/spinframework/spin-go-sdk/v3 => ../../
+
+tool github.com/bytecodealliance/componentize-go
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	spinhttp "github.com/spinframework/spin-go-sdk/v3/http"
+)
+
+func init() {
+	spinhttp.Handle(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/small":
+			// Fits in the buffer, so Content-Length is set automatically.
+			fmt.Fprint(w, "hello")
+
+		case "/large":
+			// Overflows the buffer, so the body is streamed without a
+			// Content-Length.
+			fmt.Fprint(w, strings.Repeat("x", 2*spinhttp.DefaultWriteBufferSize))
+
+		case "/flush":
+			fmt.Fprint(w, "first,")
+			w.(http.Flusher).Flush()
+			fmt.Fprint(w, "second")
+
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+			if _, err := fmt.Fprint(w, "dropped"); err != http.ErrBodyNotAllowed {
+				panic(fmt.Sprintf("write returned %v, want http.ErrBodyNotAllowed", err))
+			}
+
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+			if _, err := fmt.Fprint(w, "dropped"); err != http.ErrBodyNotAllowed {
+				panic(fmt.Sprintf("write returned %v, want http.ErrBodyNotAllowed", err))
+			}
+
+		case "/trailers":
+			w.Header().Set("Trailer", "X-Checksum")
+			fmt.Fprint(w, "body")
+			w.Header().Set("X-Checksum", "declared")
+
+		case "/events":
+			stream, err := spinhttp.NewEventStream(w)
+			if err != nil {
+				return
+			}
+			stream.Send(spinhttp.Event{ID: "1", Type: "greeting", Data: "hello\nworld"})
+			stream.Comment("keep-alive")
+			stream.Send(spinhttp.Event{Data: "bye"})
+
+		case "/duplicate-header":
+			w.WriteHeader(http.StatusOK)
+			w.WriteHeader(http.StatusTeapot)
+
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func main() {}